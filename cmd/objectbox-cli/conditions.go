@@ -0,0 +1,239 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+)
+
+// property types & flags, as defined in objectbox.h
+const (
+	propertyTypeBool         = 1
+	propertyTypeByte         = 2
+	propertyTypeShort        = 3
+	propertyTypeChar         = 4
+	propertyTypeInt          = 5
+	propertyTypeLong         = 6
+	propertyTypeFloat        = 7
+	propertyTypeDouble       = 8
+	propertyTypeString       = 9
+	propertyTypeDate         = 10
+	propertyTypeRelation     = 11
+	propertyTypeDateNano     = 12
+	propertyTypeByteVector   = 23
+	propertyTypeStringVector = 30
+
+	propertyFlagId       = 1
+	propertyFlagIndexed  = 8
+	propertyFlagUnique   = 32
+	propertyFlagUnsigned = 8192
+)
+
+var propertyTypeNames = map[int]string{
+	propertyTypeBool:         "Bool",
+	propertyTypeByte:         "Byte",
+	propertyTypeShort:        "Short",
+	propertyTypeChar:         "Char",
+	propertyTypeInt:          "Int",
+	propertyTypeLong:         "Long",
+	propertyTypeFloat:        "Float",
+	propertyTypeDouble:       "Double",
+	propertyTypeString:       "String",
+	propertyTypeDate:         "Date",
+	propertyTypeRelation:     "Relation",
+	propertyTypeDateNano:     "DateNano",
+	propertyTypeByteVector:   "ByteVector",
+	propertyTypeStringVector: "StringVector",
+}
+
+func (property *storedProperty) typeName() string {
+	var name = propertyTypeNames[property.Type]
+	if len(name) == 0 {
+		name = fmt.Sprintf("Unknown(%d)", property.Type)
+	}
+	if property.Flags&propertyFlagUnsigned != 0 {
+		name = "Unsigned " + name
+	}
+	return name
+}
+
+// flagNames returns a human readable description of the most important flags
+func (property *storedProperty) flagNames() string {
+	var names []string
+	if property.Flags&propertyFlagId != 0 {
+		names = append(names, "id")
+	}
+	if property.Flags&propertyFlagIndexed != 0 {
+		names = append(names, "index")
+	}
+	if property.Flags&propertyFlagUnique != 0 {
+		names = append(names, "unique")
+	}
+	if len(property.RelationTarget) > 0 {
+		names = append(names, "link("+property.RelationTarget+")")
+	}
+	return strings.Join(names, ",")
+}
+
+// operators which don't take a value
+var unaryOperators = map[string]bool{"isnil": true, "notnil": true}
+
+const conditionsHelp = `Conditions are given as "<property> <operator> [<value>]" triples, combined using AND:
+  eq, ne, lt, le, gt, ge   compare the property with the value (eq and ne are not available for floating point types)
+  contains, prefix, suffix string properties (contains also works for string vectors)
+  isnil, notnil            check whether the property value is set (take no value)`
+
+// parseConditions creates query conditions from the command line arguments
+func parseConditions(entity *storedEntity, args []string) ([]objectbox.Condition, error) {
+	var conditions []objectbox.Condition
+	for len(args) > 0 {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("incomplete condition '%s'", strings.Join(args, " "))
+		}
+
+		property, err := entity.property(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		var operator = strings.ToLower(args[1])
+		var value string
+		if unaryOperators[operator] {
+			args = args[2:]
+		} else if len(args) < 3 {
+			return nil, fmt.Errorf("missing value for condition '%s'", strings.Join(args, " "))
+		} else {
+			value = args[2]
+			args = args[3:]
+		}
+
+		condition, err := createCondition(entity, property, operator, value)
+		if err != nil {
+			return nil, fmt.Errorf("condition on %s: %v", property.Name, err)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func createCondition(entity *storedEntity, property *storedProperty, operator, value string) (objectbox.Condition, error) {
+	var base = &objectbox.BaseProperty{
+		Id:     property.Id.id(),
+		Entity: &objectbox.Entity{Id: entity.Id.id()},
+	}
+
+	switch operator {
+	case "isnil":
+		return base.IsNil(), nil
+	case "notnil":
+		return base.IsNotNil(), nil
+	}
+
+	var unsupported = fmt.Errorf("operator '%s' is not supported for %s properties", operator, property.typeName())
+
+	switch property.Type {
+	case propertyTypeString:
+		var prop = objectbox.PropertyString{BaseProperty: base}
+		switch operator {
+		case "eq":
+			return prop.Equals(value, true), nil
+		case "ne":
+			return prop.NotEquals(value, true), nil
+		case "lt":
+			return prop.LessThan(value, true), nil
+		case "le":
+			return prop.LessOrEqual(value, true), nil
+		case "gt":
+			return prop.GreaterThan(value, true), nil
+		case "ge":
+			return prop.GreaterOrEqual(value, true), nil
+		case "contains":
+			return prop.Contains(value, true), nil
+		case "prefix":
+			return prop.HasPrefix(value, true), nil
+		case "suffix":
+			return prop.HasSuffix(value, true), nil
+		}
+		return nil, unsupported
+
+	case propertyTypeStringVector:
+		if operator == "contains" {
+			return objectbox.PropertyStringVector{BaseProperty: base}.Contains(value, true), nil
+		}
+		return nil, unsupported
+
+	case propertyTypeBool:
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		switch operator {
+		case "eq":
+			return objectbox.PropertyBool{BaseProperty: base}.Equals(flag), nil
+		case "ne":
+			return objectbox.PropertyBool{BaseProperty: base}.Equals(!flag), nil
+		}
+		return nil, unsupported
+
+	case propertyTypeByte, propertyTypeShort, propertyTypeChar, propertyTypeInt, propertyTypeLong, propertyTypeDate,
+		propertyTypeDateNano, propertyTypeRelation:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		var prop = objectbox.PropertyInt64{BaseProperty: base}
+		switch operator {
+		case "eq":
+			return prop.Equals(number), nil
+		case "ne":
+			return prop.NotEquals(number), nil
+		case "lt":
+			return prop.LessThan(number), nil
+		case "le":
+			return prop.LessOrEqual(number), nil
+		case "gt":
+			return prop.GreaterThan(number), nil
+		case "ge":
+			return prop.GreaterOrEqual(number), nil
+		}
+		return nil, unsupported
+
+	case propertyTypeFloat, propertyTypeDouble:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		var prop = objectbox.PropertyFloat64{BaseProperty: base}
+		switch operator {
+		case "lt":
+			return prop.LessThan(number), nil
+		case "le":
+			return prop.LessOrEqual(number), nil
+		case "gt":
+			return prop.GreaterThan(number), nil
+		case "ge":
+			return prop.GreaterOrEqual(number), nil
+		}
+		return nil, unsupported
+	}
+
+	return nil, unsupported
+}
@@ -0,0 +1,299 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-generator/cmd/objectbox-gogen"
+	"github.com/objectbox/objectbox-go/objectbox"
+)
+
+// storedModel is the model persisted by the generator (objectbox-model.json)
+type storedModel struct {
+	Entities       []*storedEntity `json:"entities"`
+	LastEntityId   idUid           `json:"lastEntityId"`
+	LastIndexId    idUid           `json:"lastIndexId"`
+	LastRelationId idUid           `json:"lastRelationId"`
+}
+
+type storedEntity struct {
+	Id             idUid             `json:"id"`
+	LastPropertyId idUid             `json:"lastPropertyId"`
+	Name           string            `json:"name"`
+	Flags          int               `json:"flags"`
+	Properties     []*storedProperty `json:"properties"`
+	Relations      []*storedRelation `json:"relations"`
+}
+
+type storedProperty struct {
+	Id             idUid  `json:"id"`
+	Name           string `json:"name"`
+	IndexId        idUid  `json:"indexId"`
+	Type           int    `json:"type"`
+	Flags          int    `json:"flags"`
+	RelationTarget string `json:"relationTarget"`
+}
+
+type storedRelation struct {
+	Id       idUid  `json:"id"`
+	Name     string `json:"name"`
+	TargetId idUid  `json:"targetId"`
+}
+
+// idUid is the "id:uid" format used in the model JSON
+type idUid string
+
+func (str idUid) get() (objectbox.TypeId, uint64, error) {
+	if len(str) == 0 {
+		return 0, 0, nil
+	}
+
+	var parts = strings.Split(string(str), ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid id:uid format of '%s'", str)
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid id in '%s': %v", str, err)
+	}
+
+	uid, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid uid in '%s': %v", str, err)
+	}
+
+	return objectbox.TypeId(id), uid, nil
+}
+
+// id returns only the ID part; the format has been validated by loadModel()
+func (str idUid) id() objectbox.TypeId {
+	id, _, _ := str.get()
+	return id
+}
+
+func loadModel(path string) (*storedModel, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var model = &storedModel{}
+	if err = json.Unmarshal(data, model); err != nil {
+		return nil, fmt.Errorf("can't parse model %s: %v", path, err)
+	}
+
+	// validate all IDs upfront
+	var check = func(values ...idUid) error {
+		for _, value := range values {
+			if _, _, err := value.get(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err = check(model.LastEntityId, model.LastIndexId, model.LastRelationId); err != nil {
+		return nil, err
+	}
+	for _, entity := range model.Entities {
+		if err = check(entity.Id, entity.LastPropertyId); err != nil {
+			return nil, err
+		}
+		for _, property := range entity.Properties {
+			if err = check(property.Id, property.IndexId); err != nil {
+				return nil, err
+			}
+		}
+		for _, relation := range entity.Relations {
+			if err = check(relation.Id, relation.TargetId); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return model, nil
+}
+
+// objectBoxModel creates the model for the database, equivalent to the generated ObjectBoxModel() function
+func (model *storedModel) objectBoxModel() *objectbox.Model {
+	var obModel = objectbox.NewModel()
+	obModel.GeneratorVersion(gogen.VersionId)
+
+	for _, entity := range model.Entities {
+		obModel.RegisterBinding(&entityBinding{entity: entity, model: model})
+	}
+
+	id, uid, _ := model.LastEntityId.get()
+	obModel.LastEntityId(id, uid)
+
+	if id, uid, _ = model.LastIndexId.get(); id != 0 {
+		obModel.LastIndexId(id, uid)
+	}
+
+	if id, uid, _ = model.LastRelationId.get(); id != 0 {
+		obModel.LastRelationId(id, uid)
+	}
+
+	return obModel
+}
+
+func (model *storedModel) entity(name string) (*storedEntity, error) {
+	for _, entity := range model.Entities {
+		if strings.EqualFold(entity.Name, name) {
+			return entity, nil
+		}
+	}
+	return nil, fmt.Errorf("entity '%s' not found in the model", name)
+}
+
+func (model *storedModel) entityById(id objectbox.TypeId) *storedEntity {
+	for _, entity := range model.Entities {
+		if entity.Id.id() == id {
+			return entity
+		}
+	}
+	return nil
+}
+
+func (entity *storedEntity) property(name string) (*storedProperty, error) {
+	for _, property := range entity.Properties {
+		if strings.EqualFold(property.Name, name) {
+			return property, nil
+		}
+	}
+	return nil, fmt.Errorf("property '%s' not found on entity %s", name, entity.Name)
+}
+
+func (entity *storedEntity) idProperty() *storedProperty {
+	for _, property := range entity.Properties {
+		if property.Flags&propertyFlagId != 0 {
+			return property
+		}
+	}
+	return nil
+}
+
+var errReadOnly = errors.New("objectbox-cli only supports reading raw data")
+
+// entityBinding registers an entity in the model based on the stored model information. Objects are only processed
+// as raw data (e.g. Box.ExportJSON()) so the binding doesn't support reading or writing Go structs; Load() only checks
+// the object data matches the model, see cli.verifyModel().
+type entityBinding struct {
+	entity *storedEntity
+	model  *storedModel
+}
+
+func (binding *entityBinding) AddToModel(model *objectbox.Model) {
+	id, uid, _ := binding.entity.Id.get()
+	model.Entity(binding.entity.Name, id, uid)
+	if binding.entity.Flags != 0 {
+		model.EntityFlags(binding.entity.Flags)
+	}
+
+	for _, property := range binding.entity.Properties {
+		id, uid, _ = property.Id.get()
+		model.Property(property.Name, property.Type, id, uid)
+		if property.Flags != 0 {
+			model.PropertyFlags(property.Flags)
+		}
+
+		if indexId, indexUid, _ := property.IndexId.get(); indexId != 0 {
+			if len(property.RelationTarget) > 0 {
+				model.PropertyRelation(property.RelationTarget, indexId, indexUid)
+			} else {
+				model.PropertyIndex(indexId, indexUid)
+			}
+		}
+	}
+
+	id, uid, _ = binding.entity.LastPropertyId.get()
+	model.EntityLastPropertyId(id, uid)
+
+	for _, relation := range binding.entity.Relations {
+		id, uid, _ = relation.Id.get()
+		targetId, targetUid, _ := relation.TargetId.get()
+		model.Relation(id, uid, targetId, targetUid)
+	}
+}
+
+func (binding *entityBinding) GetId(object interface{}) (uint64, error) {
+	return 0, errReadOnly
+}
+
+func (binding *entityBinding) SetId(object interface{}, id uint64) error {
+	return errReadOnly
+}
+
+func (binding *entityBinding) PutRelated(ob *objectbox.ObjectBox, object interface{}, id uint64) error {
+	return errReadOnly
+}
+
+func (binding *entityBinding) Flatten(object interface{}, fbb *flatbuffers.Builder, id uint64) error {
+	return errReadOnly
+}
+
+// Load verifies all the fields present in the object data are declared as properties in the model
+func (binding *entityBinding) Load(ob *objectbox.ObjectBox, bytes []byte) (interface{}, error) {
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+
+	// the vtable starts with its own size in bytes and the table size, followed by field offsets (one per property ID)
+	var vtable = flatbuffers.UOffsetT(flatbuffers.SOffsetT(table.Pos) - table.GetSOffsetT(table.Pos))
+	var fieldsCount = (int(table.GetVOffsetT(vtable)) - 4) / 2
+
+	for i := 0; i < fieldsCount; i++ {
+		if table.Offset(flatbuffers.VOffsetT(4+2*i)) == 0 {
+			continue
+		}
+
+		var found = false
+		for _, property := range binding.entity.Properties {
+			if int(property.Id.id()) == i+1 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("object data contains property ID %d, which is not in the model", i+1)
+		}
+	}
+
+	// the data itself isn't used; it must not be kept anyway because it's only valid during the read
+	return struct{}{}, nil
+}
+
+func (binding *entityBinding) MakeSlice(capacity int) interface{} {
+	return make([]interface{}, 0, capacity)
+}
+
+func (binding *entityBinding) AppendToSlice(slice interface{}, object interface{}) interface{} {
+	return append(slice.([]interface{}), object)
+}
+
+func (binding *entityBinding) GeneratorVersion() int {
+	return gogen.VersionId
+}
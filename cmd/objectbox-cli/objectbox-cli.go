@@ -0,0 +1,308 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Inspects an ObjectBox database without the need to write Go code; the database is opened in read-only mode.
+
+The database schema is read from the model JSON file persisted by objectbox-gogen (objectbox-model.json),
+i.e. the Go structs (bindings) are not necessary. The model file is required because the ObjectBox C API doesn't
+provide a way to enumerate the schema stored in the database. Therefore, use the model file of the application version
+which last wrote to the database. When opening the database, the command checks a sample of the stored objects of each
+entity and fails if they contain properties missing in the model file, i.e. if the model file is older than the data.
+
+Usage:
+
+	objectbox-cli [flags] {command} [arguments]
+
+Commands:
+	entities
+		lists all entities with their IDs and object counts
+	schema [entity...]
+		prints properties and relations of the given (or all) entities
+	count {entity} [conditions...]
+		prints the number of objects (matching the conditions)
+	get {entity} {id...}
+		prints the objects with the given IDs as JSON
+	query {entity} [conditions...]
+		prints the objects matching the conditions as JSON
+
+Conditions are given as "{property} {operator} [value]" triples, combined using AND, e.g.
+	objectbox-cli -model model/objectbox-model.json query Task Text contains milk DateFinished isnil
+
+Available flags:
+  -dir string
+    	database directory (default "objectbox")
+  -limit uint
+    	maximum number of objects printed by the query command (0 = no limit)
+  -model string
+    	path to the model JSON file (objectbox-model.json)
+  -offset uint
+    	number of objects skipped by the query command
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+)
+
+func main() {
+	var dir = flag.String("dir", "objectbox", "database directory")
+	var modelFile = flag.String("model", "", "path to the model JSON file (objectbox-model.json)")
+	var limit = flag.Uint64("limit", 0, "maximum number of objects printed by the query command (0 = no limit)")
+	var offset = flag.Uint64("offset", 0, "number of objects skipped by the query command")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] {entities|schema|count|get|query} [arguments]\n\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nThe model file is required because the schema stored in the database "+
+			"can't be read through the ObjectBox C API;\nit must match the database, which is verified on a sample of "+
+			"the stored objects.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "\n%s\n", conditionsHelp)
+	}
+	flag.Parse()
+
+	if len(*modelFile) == 0 || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var cli = &cli{out: os.Stdout, limit: *limit, offset: *offset}
+	if err := cli.open(*dir, *modelFile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var err = cli.run(flag.Arg(0), flag.Args()[1:])
+	cli.ob.Close()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+type cli struct {
+	out    io.Writer
+	model  *storedModel
+	ob     *objectbox.ObjectBox
+	limit  uint64
+	offset uint64
+}
+
+func (cli *cli) open(dir, modelFile string) (err error) {
+	if _, err = os.Stat(dir); err != nil {
+		return fmt.Errorf("can't open database directory: %v", err)
+	}
+
+	if cli.model, err = loadModel(modelFile); err != nil {
+		return err
+	}
+
+	cli.ob, err = objectbox.NewBuilder().Directory(dir).Model(cli.model.objectBoxModel()).ReadOnly().BuildOrError()
+	if err != nil {
+		return fmt.Errorf("can't open the database with the model %s: %v", modelFile, err)
+	}
+
+	if err = cli.verifyModel(); err != nil {
+		cli.ob.Close()
+		return fmt.Errorf("the model %s doesn't match the database: %v", modelFile, err)
+	}
+	return nil
+}
+
+// verifyModel reads up to modelCheckLimit objects of each entity, failing if they don't match the model,
+// see entityBinding.Load()
+func (cli *cli) verifyModel() error {
+	const modelCheckLimit = 1000
+	for _, entity := range cli.model.Entities {
+		query, err := cli.ob.InternalBox(entity.Id.id()).QueryOrError()
+		if err != nil {
+			return err
+		}
+
+		_, err = query.Limit(modelCheckLimit).Find()
+		query.Close()
+		if err != nil {
+			return fmt.Errorf("entity %s: %v", entity.Name, err)
+		}
+	}
+	return nil
+}
+
+func (cli *cli) run(command string, args []string) error {
+	switch command {
+	case "entities":
+		return cli.entities()
+	case "schema":
+		return cli.schema(args)
+	case "count":
+		return cli.count(args)
+	case "get":
+		return cli.get(args)
+	case "query":
+		return cli.query(args)
+	default:
+		return fmt.Errorf("unknown command '%s'", command)
+	}
+}
+
+func (cli *cli) entities() error {
+	var w = tabwriter.NewWriter(cli.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEntity\tCount")
+	for _, entity := range cli.model.Entities {
+		count, err := cli.ob.InternalBox(entity.Id.id()).Count()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\n", entity.Id.id(), entity.Name, count)
+	}
+	return w.Flush()
+}
+
+func (cli *cli) schema(args []string) error {
+	var entities = cli.model.Entities
+	if len(args) > 0 {
+		entities = nil
+		for _, name := range args {
+			entity, err := cli.model.entity(name)
+			if err != nil {
+				return err
+			}
+			entities = append(entities, entity)
+		}
+	}
+
+	for i, entity := range entities {
+		if i > 0 {
+			fmt.Fprintln(cli.out)
+		}
+		fmt.Fprintf(cli.out, "%s (ID %d)\n", entity.Name, entity.Id.id())
+
+		var w = tabwriter.NewWriter(cli.out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  ID\tProperty\tType\tFlags")
+		for _, property := range entity.Properties {
+			fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", property.Id.id(), property.Name, property.typeName(), property.flagNames())
+		}
+		for _, relation := range entity.Relations {
+			var target = "?"
+			if targetEntity := cli.model.entityById(relation.TargetId.id()); targetEntity != nil {
+				target = targetEntity.Name
+			}
+			fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", relation.Id.id(), relation.Name, "Relation to-many", "link("+target+")")
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cli *cli) count(args []string) error {
+	if len(args) == 0 {
+		return errors.New("entity name missing")
+	}
+
+	query, err := cli.createQuery(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	defer query.Close()
+
+	count, err := query.Count()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cli.out, count)
+	return err
+}
+
+func (cli *cli) get(args []string) error {
+	if len(args) < 2 {
+		return errors.New("entity name or object IDs missing")
+	}
+
+	entity, err := cli.model.entity(args[0])
+	if err != nil {
+		return err
+	}
+
+	var idProperty = entity.idProperty()
+	if idProperty == nil {
+		return fmt.Errorf("entity %s doesn't have an ID property", entity.Name)
+	}
+
+	var ids = make([]int64, len(args)-1)
+	for i, arg := range args[1:] {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid object ID '%s': %v", arg, err)
+		}
+		ids[i] = int64(id)
+	}
+
+	var condition = objectbox.PropertyInt64{BaseProperty: &objectbox.BaseProperty{
+		Id:     idProperty.Id.id(),
+		Entity: &objectbox.Entity{Id: entity.Id.id()},
+	}}.In(ids...)
+
+	query, err := cli.ob.InternalBox(entity.Id.id()).QueryOrError(condition)
+	if err != nil {
+		return err
+	}
+	defer query.Close()
+	return query.ExportJSON(cli.out)
+}
+
+func (cli *cli) query(args []string) error {
+	if len(args) == 0 {
+		return errors.New("entity name missing")
+	}
+
+	query, err := cli.createQuery(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	defer query.Close()
+
+	if cli.offset > 0 {
+		query.Offset(cli.offset)
+	}
+	if cli.limit > 0 {
+		query.Limit(cli.limit)
+	}
+	return query.ExportJSON(cli.out)
+}
+
+func (cli *cli) createQuery(entityName string, conditionArgs []string) (*objectbox.Query, error) {
+	entity, err := cli.model.entity(entityName)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, err := parseConditions(entity, conditionArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.ob.InternalBox(entity.Id.id()).QueryOrError(conditions...)
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+const modelFile = "../../test/model/objectbox-model.json"
+
+func TestLoadModel(t *testing.T) {
+	stored, err := loadModel(modelFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, 5, len(stored.Entities))
+
+	entity, err := stored.entity("testentityrelated")
+	assert.NoErr(t, err)
+	assert.Eq(t, "TestEntityRelated", entity.Name)
+	assert.Eq(t, "Id", entity.idProperty().Name)
+	assert.Eq(t, 1, len(entity.Relations))
+
+	_, err = stored.entity("missing")
+	assert.Err(t, err)
+
+	_, err = loadModel("missing.json")
+	assert.Err(t, err)
+}
+
+func TestCommands(t *testing.T) {
+	var dir = t.TempDir()
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+
+	var box = model.BoxForEntity(ob)
+	for i := 1; i <= 5; i++ {
+		_, err = box.Put(&model.Entity{Int64: int64(i), String: fmt.Sprintf("value %d", i), StringVector: []string{"a", "b"}})
+		assert.NoErr(t, err)
+	}
+	ob.Close()
+
+	var out bytes.Buffer
+	var cli = &cli{out: &out}
+	assert.NoErr(t, cli.open(dir, modelFile))
+	defer cli.ob.Close()
+
+	var run = func(args ...string) string {
+		out.Reset()
+		assert.NoErr(t, cli.run(args[0], args[1:]))
+		return out.String()
+	}
+
+	var entities = run("entities")
+	assert.True(t, strings.Contains(entities, "TestEntityRelated"))
+	assert.True(t, strings.Contains(entities, "EntityByValue"))
+
+	var schema = run("schema", "Entity")
+	assert.True(t, strings.Contains(schema, "StringVector"))
+	assert.True(t, strings.Contains(schema, "link(TestEntityRelated)"))
+
+	assert.Eq(t, "5\n", run("count", "Entity"))
+	assert.Eq(t, "2\n", run("count", "Entity", "Int64", "gt", "3"))
+	assert.Eq(t, "1\n", run("count", "Entity", "String", "eq", "value 2"))
+	assert.Eq(t, "5\n", run("count", "Entity", "StringVector", "contains", "b", "IntPtr", "isnil"))
+
+	var objects []map[string]interface{}
+	assert.NoErr(t, json.Unmarshal([]byte(run("get", "Entity", "1", "3")), &objects))
+	assert.Eq(t, 2, len(objects))
+	assert.Eq(t, "value 3", objects[1]["String"])
+
+	cli.limit = 2
+	assert.NoErr(t, json.Unmarshal([]byte(run("query", "Entity", "String", "prefix", "value")), &objects))
+	assert.Eq(t, 2, len(objects))
+
+	assert.Err(t, cli.run("unknown", nil))
+	assert.Err(t, cli.run("count", []string{"Entity", "Float64", "eq", "1"}))
+	assert.Err(t, cli.run("count", []string{"Entity", "Missing", "eq", "1"}))
+	assert.Err(t, cli.run("count", []string{"Entity", "Int64", "gt"}))
+}
+
+func TestModelMismatch(t *testing.T) {
+	var dir = t.TempDir()
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	_, err = model.BoxForEntity(ob).Put(&model.Entity{String: "value"})
+	assert.NoErr(t, err)
+	ob.Close()
+
+	// an older model file, without the property String
+	stored, err := loadModel(modelFile)
+	assert.NoErr(t, err)
+	entity, err := stored.entity("Entity")
+	assert.NoErr(t, err)
+	for i, property := range entity.Properties {
+		if property.Name == "String" {
+			entity.Properties = append(entity.Properties[:i], entity.Properties[i+1:]...)
+			break
+		}
+	}
+
+	data, err := json.Marshal(stored)
+	assert.NoErr(t, err)
+	var olderModelFile = filepath.Join(t.TempDir(), "objectbox-model.json")
+	assert.NoErr(t, ioutil.WriteFile(olderModelFile, data, 0644))
+
+	var cli = &cli{out: &bytes.Buffer{}}
+	err = cli.open(dir, olderModelFile)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "doesn't match"))
+}
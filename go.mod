@@ -1,6 +1,6 @@
 module github.com/objectbox/objectbox-go
 
-go 1.11
+go 1.18
 
 require (
 	github.com/google/flatbuffers v1.12.0
@@ -22,6 +22,7 @@ package objectbox
 */
 import "C"
 import (
+	"context"
 	"errors"
 	"unsafe"
 )
@@ -71,7 +72,14 @@ func NewAsyncBox(ob *ObjectBox, entityId TypeId, timeoutMs uint64) (*AsyncBox, e
 // Not necessary for the standard (shared) instance from box.Async(); Close() can still be called for those:
 // it just won't have any effect.
 func (async *AsyncBox) Close() error {
-	if !async.cOwned || async.cAsync == nil {
+	if !async.cOwned {
+		return nil
+	}
+	return async.close()
+}
+
+func (async *AsyncBox) close() error {
+	if async.cAsync == nil {
 		return nil
 	}
 	var cAsync = async.cAsync
@@ -104,6 +112,8 @@ func (async *AsyncBox) put(object interface{}, mode int) (uint64, error) {
 				C.OBXPutMode(mode))
 		})
 	})
+	async.box.ObjectBox.asyncSubmitted(err)
+	async.logSubmission(asyncPutOperations[mode], id, err)
 
 	if err != nil {
 		return 0, err
@@ -140,6 +150,7 @@ func (async *AsyncBox) Update(object interface{}) error {
 }
 
 // Remove deletes a single object asynchronously.
+// Remove policies of relations pointing to the object are not applied, see RemovePolicy.
 func (async *AsyncBox) Remove(object interface{}) error {
 	id, err := async.box.entity.binding.GetId(object)
 	if err != nil {
@@ -150,10 +161,14 @@ func (async *AsyncBox) Remove(object interface{}) error {
 }
 
 // RemoveId deletes a single object asynchronously.
+// Remove policies of relations pointing to the object are not applied, see RemovePolicy.
 func (async *AsyncBox) RemoveId(id uint64) error {
-	return cCall(func() C.obx_err {
+	var err = cCall(func() C.obx_err {
 		return C.obx_async_remove(async.cAsync, C.obx_id(id))
 	})
+	async.box.ObjectBox.asyncSubmitted(err)
+	async.logSubmission("remove", id, err)
+	return err
 }
 
 // AwaitCompletion waits for all (including future) async submissions to be completed (the async queue becomes idle for
@@ -165,6 +180,20 @@ func (async *AsyncBox) AwaitCompletion() error {
 	})
 }
 
+// AwaitCompletionCtx is like AwaitCompletion() but returns the context's error as soon as the given context is
+// cancelled or its deadline exceeded. Note: the async queue itself is not affected by the context.
+// Note: if the context is done first, the native wait is left running in a background goroutine until it returns.
+func (async *AsyncBox) AwaitCompletionCtx(ctx context.Context) error {
+	return awaitCtx(ctx, async.AwaitCompletion)
+}
+
+// AwaitSubmittedCtx is like AwaitSubmitted() but returns the context's error as soon as the given context is
+// cancelled or its deadline exceeded. Note: the async queue itself is not affected by the context.
+// Note: if the context is done first, the native wait is left running in a background goroutine until it returns.
+func (async *AsyncBox) AwaitSubmittedCtx(ctx context.Context) error {
+	return awaitCtx(ctx, async.AwaitSubmitted)
+}
+
 // AwaitSubmitted for previously submitted async operations to be completed (the async queue does not have to become idle).
 // Currently this is not limited to the single entity this AsyncBox is working on but all entities in the store.
 // Returns an error if shutting down or an error occurred
@@ -0,0 +1,441 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"unsafe"
+)
+
+// Backup file format (all numbers little-endian):
+//   header:   magic "OBXGOBAK", uint32 format version, uint32 schema version (since format version 2)
+//   objects:  'E', uint32 entity ID, {uint64 object ID, uint32 size, FlatBuffers bytes}..., uint64 0
+//   relation: 'R', uint32 source entity ID, uint32 relation ID, {uint64 source ID, uint32 count, uint64 target IDs}..., uint64 0
+//   footer:   'Z'
+var backupMagic = [8]byte{'O', 'B', 'X', 'G', 'O', 'B', 'A', 'K'}
+
+const backupFormatVersion = 2
+
+const (
+	backupTagEntity   = 'E'
+	backupTagRelation = 'R'
+	backupTagEnd      = 'Z'
+)
+
+// Backup writes a consistent snapshot of all objects and standalone relations to the given writer.
+// The data is read inside a single read transaction, so it's safe to call while the database is being written to
+// (concurrent writes are not blocked and won't be part of the backup).
+// Use Builder.RestoreFromFile() to open a store from the backup.
+func (ob *ObjectBox) Backup(w io.Writer) error {
+	var out = bufio.NewWriter(w)
+	var write = func(data interface{}) error {
+		return binary.Write(out, binary.LittleEndian, data)
+	}
+
+	var err = ob.RunInReadTx(func() error {
+		if err := write(backupMagic); err != nil {
+			return err
+		}
+		if err := write(uint32(backupFormatVersion)); err != nil {
+			return err
+		}
+
+		// the restored database must continue with the migrations following this version, see Builder.Migration();
+		// it's read from the same snapshot as the data
+		schemaVersion, err := ob.loadSchemaVersion()
+		if err != nil {
+			return err
+		}
+		if err := write(uint32(schemaVersion)); err != nil {
+			return err
+		}
+
+		var entityIds = ob.sortedEntityIds()
+		var objectIds = make(map[TypeId][]uint64, len(entityIds))
+
+		for _, entityId := range entityIds {
+			box, err := ob.box(entityId)
+			if err != nil {
+				return err
+			}
+
+			if objectIds[entityId], err = box.allIds(); err != nil {
+				return err
+			}
+
+			if err = backupObjects(box, objectIds[entityId], write, out); err != nil {
+				return err
+			}
+		}
+
+		// relations are written after all objects so that both sources and targets exist when restoring
+		for _, entityId := range entityIds {
+			var box = ob.InternalBox(entityId)
+			for _, relation := range box.entity.relations {
+				if err := backupRelation(box, relation, objectIds[entityId], write); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = out.WriteByte(backupTagEnd); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// BackupToFile writes a consistent snapshot of the database to the given file, see Backup().
+// The file is created (or truncated if it exists).
+func (ob *ObjectBox) BackupToFile(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err2 := file.Close(); err == nil {
+			err = err2
+		}
+	}()
+	return ob.Backup(file)
+}
+
+func (ob *ObjectBox) sortedEntityIds() []TypeId {
+	var ids = make([]TypeId, 0, len(ob.entitiesById))
+	for id := range ob.entitiesById {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// allIds returns IDs of all stored objects, in ascending order
+func (box *Box) allIds() ([]uint64, error) {
+	query, err := box.QueryOrError()
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+	return query.FindIds()
+}
+
+// backupObjects must be called inside a read transaction
+func backupObjects(box *Box, ids []uint64, write func(interface{}) error, out io.Writer) error {
+	if err := write(uint8(backupTagEntity)); err != nil {
+		return err
+	}
+	if err := write(uint32(box.entity.id)); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		var data *C.void
+		var dataSize C.size_t
+		var dataPtr = unsafe.Pointer(data)
+
+		if err := cCall(func() C.obx_err {
+			return C.obx_box_get(box.cBox, C.obx_id(id), &dataPtr, &dataSize)
+		}); err != nil {
+			return err
+		}
+
+		var bytes []byte
+		cVoidPtrToByteSlice(dataPtr, int(dataSize), &bytes)
+
+		if err := write(id); err != nil {
+			return err
+		}
+		if err := write(uint32(len(bytes))); err != nil {
+			return err
+		}
+		if _, err := out.Write(bytes); err != nil {
+			return err
+		}
+	}
+
+	return write(uint64(0))
+}
+
+// backupRelation must be called inside a read transaction
+func backupRelation(box *Box, relation RelationInfo, sourceIds []uint64, write func(interface{}) error) error {
+	var relationId = relation.Id
+
+	// obx_box_rel_get_ids() works on the target box of the relation
+	targetBox, err := box.ObjectBox.box(relation.TargetEntityId)
+	if err != nil {
+		return err
+	}
+
+	if err := write(uint8(backupTagRelation)); err != nil {
+		return err
+	}
+	if err := write(uint32(box.entity.id)); err != nil {
+		return err
+	}
+	if err := write(uint32(relationId)); err != nil {
+		return err
+	}
+
+	for _, sourceId := range sourceIds {
+		targetIds, err := cGetIds(func() *C.OBX_id_array {
+			return C.obx_box_rel_get_ids(targetBox.cBox, C.obx_schema_id(relationId), C.obx_id(sourceId))
+		})
+		if err != nil {
+			return err
+		}
+		if len(targetIds) == 0 {
+			continue
+		}
+
+		if err = write(sourceId); err != nil {
+			return err
+		}
+		if err = write(uint32(len(targetIds))); err != nil {
+			return err
+		}
+		if err = write(targetIds); err != nil {
+			return err
+		}
+	}
+
+	return write(uint64(0))
+}
+
+// restoreFromFile reads a backup created by Backup() into this (empty) store
+func (ob *ObjectBox) restoreFromFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return ob.restore(file)
+}
+
+func (ob *ObjectBox) restore(r io.Reader) error {
+	var in = bufio.NewReader(r)
+	var read = func(data interface{}) error {
+		return binary.Read(in, binary.LittleEndian, data)
+	}
+
+	var magic [8]byte
+	if err := read(&magic); err != nil {
+		return fmt.Errorf("invalid backup: %v", err)
+	} else if magic != backupMagic {
+		return errors.New("invalid backup: unrecognized file format")
+	}
+
+	var version uint32
+	if err := read(&version); err != nil {
+		return fmt.Errorf("invalid backup: %v", err)
+	} else if version < 1 || version > backupFormatVersion {
+		return fmt.Errorf("unsupported backup format version %d, expected 1 to %d", version, backupFormatVersion)
+	}
+
+	// backups written before the schema version was recorded are considered version 0, same as the database
+	var schemaVersion uint32
+	if version >= 2 {
+		if err := read(&schemaVersion); err != nil {
+			return fmt.Errorf("invalid backup: %v", err)
+		}
+	}
+
+	// the schema version is stored in the same transaction as the data
+	return ob.RunInWriteTx(func() error {
+		for _, entityId := range ob.sortedEntityIds() {
+			if empty, err := ob.InternalBox(entityId).IsEmpty(); err != nil {
+				return err
+			} else if !empty {
+				return fmt.Errorf("can't restore a backup into a non-empty database, entity ID %d already contains data", entityId)
+			}
+		}
+
+		for {
+			tag, err := in.ReadByte()
+			if err != nil {
+				return fmt.Errorf("invalid backup: %v", err)
+			}
+
+			switch tag {
+			case backupTagEntity:
+				err = ob.restoreObjects(read, in)
+			case backupTagRelation:
+				err = ob.restoreRelation(read)
+			case backupTagEnd:
+				return ob.storeSchemaVersion(uint(schemaVersion))
+			default:
+				err = fmt.Errorf("invalid backup: unknown section tag %d", tag)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// restoreBox returns a box for the entity with the given ID, as read from the backup
+func (ob *ObjectBox) restoreBox(entityId uint32) (*Box, error) {
+	if ob.entitiesById[TypeId(entityId)] == nil {
+		return nil, fmt.Errorf("backup contains entity ID %d which is not present in the current model", entityId)
+	}
+	return ob.box(TypeId(entityId))
+}
+
+// restoreObjects must be called inside a write transaction
+func (ob *ObjectBox) restoreObjects(read func(interface{}) error, in io.Reader) error {
+	var entityId uint32
+	if err := read(&entityId); err != nil {
+		return err
+	}
+
+	box, err := ob.restoreBox(entityId)
+	if err != nil {
+		return err
+	}
+
+	// the ID sequence of the new store must be advanced before objects can be put with their original IDs
+	var reservedUpTo uint64
+
+	// the data is read into a buffer growing with the data actually read, not by the size given in the (possibly
+	// corrupted) file
+	var buffer bytes.Buffer
+	for {
+		var id uint64
+		if err := read(&id); err != nil {
+			return err
+		} else if id == 0 {
+			return nil
+		}
+
+		var size uint32
+		if err := read(&size); err != nil {
+			return err
+		} else if size == 0 {
+			return fmt.Errorf("invalid backup: empty data for entity ID %d object ID %d", entityId, id)
+		}
+
+		buffer.Reset()
+		if _, err := io.CopyN(&buffer, in, int64(size)); err != nil {
+			return fmt.Errorf("invalid backup: can't read data of entity ID %d object ID %d: %v", entityId, id, err)
+		}
+		var data = buffer.Bytes()
+
+		if reservedUpTo, err = box.reserveIdsUpTo(id, reservedUpTo); err != nil {
+			return err
+		}
+
+		if err := cCall(func() C.obx_err {
+			return C.obx_box_put5(box.cBox, C.obx_id(id), unsafe.Pointer(&data[0]), C.size_t(len(data)), cPutModePut)
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// reserveIdsUpTo advances the ID sequence (if necessary) so that an object with the given ID can be put, even if it
+// wasn't assigned by this store (e.g. when restoring/importing data). Returns the highest ID known to be reserved,
+// which should be passed as `reservedUpTo` to the next call to avoid unnecessary sequence increments.
+// Must be called inside a write transaction.
+func (box *Box) reserveIdsUpTo(id uint64, reservedUpTo uint64) (uint64, error) {
+	if id <= reservedUpTo {
+		return reservedUpTo, nil
+	}
+
+	next, err := box.idForPut(0)
+	if err != nil {
+		return reservedUpTo, err
+	}
+
+	if next < id {
+		if _, err = box.idsForPut(int(id - next)); err != nil {
+			return reservedUpTo, err
+		}
+		return id, nil
+	}
+	return next, nil
+}
+
+// restoreRelationChunkSize is the maximum number of relation target IDs read from a backup at once
+const restoreRelationChunkSize = 1024
+
+// restoreRelation must be called inside a write transaction
+func (ob *ObjectBox) restoreRelation(read func(interface{}) error) error {
+	var entityId, relationId uint32
+	if err := read(&entityId); err != nil {
+		return err
+	}
+	if err := read(&relationId); err != nil {
+		return err
+	}
+
+	box, err := ob.restoreBox(entityId)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var sourceId uint64
+		if err := read(&sourceId); err != nil {
+			return err
+		} else if sourceId == 0 {
+			return nil
+		}
+
+		var count uint32
+		if err := read(&count); err != nil {
+			return err
+		}
+
+		// read in chunks so that a corrupted count can't cause a huge allocation
+		var chunk = make([]uint64, restoreRelationChunkSize)
+		for count > 0 {
+			var targetIds = chunk
+			if count < uint32(len(chunk)) {
+				targetIds = chunk[:count]
+			}
+			if err := read(targetIds); err != nil {
+				return err
+			}
+			count -= uint32(len(targetIds))
+
+			for _, targetId := range targetIds {
+				if err := cCall(func() C.obx_err {
+					return C.obx_box_rel_put(box.cBox, C.obx_schema_id(relationId), C.obx_id(sourceId), C.obx_id(targetId))
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
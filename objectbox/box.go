@@ -23,6 +23,7 @@ package objectbox
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -38,6 +39,9 @@ type Box struct {
 	entity    *entity
 	cBox      *C.OBX_box
 	async     *AsyncBox
+
+	// the transaction a box returned by Tx.Box() is bound to; nil for regular boxes
+	tx *Tx
 }
 
 const defaultSliceCapacity = 16
@@ -56,12 +60,17 @@ func newBox(ob *ObjectBox, entityId TypeId) (*Box, error) {
 	}
 
 	// NOTE this is different than NewAsyncBox in that it doesn't require explicit closing
+	// (an instance with a custom timeout is closed together with the ObjectBox)
 	box.async = &AsyncBox{
 		box:    box,
 		cOwned: false,
 	}
 	if err := cCallBool(func() bool {
-		box.async.cAsync = C.obx_async(box.cBox)
+		if ob.options.asyncTimeout > 0 {
+			box.async.cAsync = C.obx_async_create(box.cBox, C.uint64_t(ob.options.asyncTimeout))
+		} else {
+			box.async.cAsync = C.obx_async(box.cBox)
+		}
 		return box.async.cAsync != nil
 	}); err != nil {
 		return nil, err
@@ -89,6 +98,10 @@ func (box *Box) Query(conditions ...Condition) *Query {
 
 // QueryOrError is like Query() but with error handling; e.g. when you build conditions dynamically that may fail.
 func (box *Box) QueryOrError(conditions ...Condition) (query *Query, err error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
 	builder := newQueryBuilder(box.ObjectBox, box.entity.id)
 
 	defer func() {
@@ -104,10 +117,25 @@ func (box *Box) QueryOrError(conditions ...Condition) (query *Query, err error)
 	}
 
 	query, err = builder.Build(box)
+	if err == nil {
+		query.conditions = conditions
+	}
 
 	return // NOTE result might be overwritten by the deferred "closer" function
 }
 
+// checkTx verifies a box returned by Tx.Box() is used while its transaction is open and from the goroutine which
+// created it; operations executed on another thread wouldn't be part of the transaction.
+func (box *Box) checkTx() error {
+	if box.tx == nil {
+		return nil
+	}
+
+	box.tx.mutex.Lock()
+	defer box.tx.mutex.Unlock()
+	return box.tx.check()
+}
+
 func (box *Box) idForPut(idCandidate uint64) (id uint64, err error) {
 	id = uint64(C.obx_box_id_for_put(box.cBox, C.obx_id(idCandidate)))
 
@@ -142,6 +170,10 @@ func (box *Box) idsForPut(count int) (firstId uint64, err error) {
 }
 
 func (box *Box) put(object interface{}, alreadyInTx bool, putMode C.OBXPutMode) (id uint64, err error) {
+	if err := box.checkTx(); err != nil {
+		return 0, err
+	}
+
 	idFromObject, err := box.entity.binding.GetId(object)
 	if err != nil {
 		return 0, err
@@ -252,6 +284,10 @@ func (box *Box) Update(object interface{}) error {
 //
 // Note: The slice may be empty or even nil; in both cases, an empty IDs slice and no error is returned.
 func (box *Box) PutMany(objects interface{}) (ids []uint64, err error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
 	var slice = reflect.ValueOf(objects)
 	var count = slice.Len()
 
@@ -403,9 +439,20 @@ func (box *Box) Remove(object interface{}) error {
 
 // RemoveId deletes a single object
 func (box *Box) RemoveId(id uint64) error {
-	return cCall(func() C.obx_err {
-		return C.obx_box_remove(box.cBox, C.obx_id(id))
-	})
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
+	var remove = func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_box_remove(box.cBox, C.obx_id(id))
+		})
+	}
+
+	if len(box.entity.incomingRelations) > 0 {
+		return box.removeWithPolicies(func() ([]uint64, error) { return []uint64{id}, nil }, remove)
+	}
+	return remove()
 }
 
 // RemoveIds deletes multiple objects at once.
@@ -414,24 +461,54 @@ func (box *Box) RemoveId(id uint64) error {
 // In case you need to strictly check whether all of the objects exist before removing them,
 // you can execute multiple box.Contains() and box.Remove() inside a single write transaction.
 func (box *Box) RemoveIds(ids ...uint64) (uint64, error) {
+	if err := box.checkTx(); err != nil {
+		return 0, err
+	}
+
 	cIds, err := goIdsArrayToC(ids)
 	if err != nil {
 		return 0, err
 	}
 
 	var cResult C.uint64_t
-	err = cCall(func() C.obx_err {
-		return C.obx_box_remove_many(box.cBox, cIds.cArray, &cResult)
-	})
+	var remove = func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_box_remove_many(box.cBox, cIds.cArray, &cResult)
+		})
+	}
+
+	if len(box.entity.incomingRelations) > 0 {
+		err = box.removeWithPolicies(func() ([]uint64, error) { return ids, nil }, remove)
+	} else {
+		err = remove()
+	}
 	return uint64(cResult), err
 }
 
 // RemoveAll removes all stored objects.
 // This is much faster than removing objects one by one in a loop.
 func (box *Box) RemoveAll() error {
-	return cCall(func() C.obx_err {
-		return C.obx_box_remove_all(box.cBox, nil)
-	})
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
+	var remove = func() error {
+		return cCall(func() C.obx_err {
+			return C.obx_box_remove_all(box.cBox, nil)
+		})
+	}
+
+	if len(box.entity.incomingRelations) > 0 {
+		return box.removeWithPolicies(func() ([]uint64, error) {
+			query, err := box.QueryOrError()
+			if err != nil {
+				return nil, err
+			}
+			defer query.Close()
+			return query.FindIds()
+		}, remove)
+	}
+	return remove()
 }
 
 // Count returns a number of objects stored
@@ -439,9 +516,22 @@ func (box *Box) Count() (uint64, error) {
 	return box.CountMax(0)
 }
 
+// CountCtx is like Count() but returns the context's error if the given context is already done.
+// Note: the native count itself can't be interrupted.
+func (box *Box) CountCtx(ctx context.Context) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return box.Count()
+}
+
 // CountMax returns a number of objects stored (up to a given maximum)
 // passing limit=0 is the same as calling Count() - counts all objects without a limit
 func (box *Box) CountMax(limit uint64) (uint64, error) {
+	if err := box.checkTx(); err != nil {
+		return 0, err
+	}
+
 	var cResult C.uint64_t
 	if err := cCall(func() C.obx_err { return C.obx_box_count(box.cBox, C.uint64_t(limit), &cResult) }); err != nil {
 		return 0, err
@@ -451,6 +541,10 @@ func (box *Box) CountMax(limit uint64) (uint64, error) {
 
 // IsEmpty checks whether the box contains any objects
 func (box *Box) IsEmpty() (bool, error) {
+	if err := box.checkTx(); err != nil {
+		return false, err
+	}
+
 	var cResult C.bool
 	if err := cCall(func() C.obx_err { return C.obx_box_is_empty(box.cBox, &cResult) }); err != nil {
 		return false, err
@@ -464,6 +558,10 @@ func (box *Box) IsEmpty() (bool, error) {
 // Returns nil in case the object with the given ID doesn't exist.
 // The cast is done automatically when using the generated BoxFor* code.
 func (box *Box) Get(id uint64) (object interface{}, err error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
 	// we need a read-transaction to keep the data in dataPtr untouched (by concurrent write) until we can read it
 	// as well as making sure the relations read in binding.Load represent a consistent state
 	err = box.ObjectBox.RunInReadTx(func() error {
@@ -491,6 +589,15 @@ func (box *Box) Get(id uint64) (object interface{}, err error) {
 	return object, err
 }
 
+// GetCtx is like Get() but returns the context's error if the given context is already done.
+// Note: the native read of a single object can't be interrupted.
+func (box *Box) GetCtx(ctx context.Context, id uint64) (object interface{}, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return box.Get(id)
+}
+
 // GetMany reads multiple objects at once.
 //
 // Returns a slice of objects that should be cast to the appropriate type.
@@ -507,10 +614,26 @@ func (box *Box) GetMany(ids ...uint64) (slice interface{}, err error) {
 		var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 			return C.obx_box_visit_many(box.cBox, cIds.cArray, dataVisitor, visitorArg)
 		}
-		return box.readUsingVisitor(existingOnly, cFn)
+		return box.readUsingVisitor(context.Background(), existingOnly, cFn)
 	}
 }
 
+// GetManyCtx is like GetMany() but reads the objects one by one, checking the given context in between.
+// The read is aborted and the context's error is returned as soon as the context is cancelled or its deadline exceeded.
+func (box *Box) GetManyCtx(ctx context.Context, ids ...uint64) (slice interface{}, err error) {
+	const existingOnly = false
+	cIds, err := goIdsArrayToC(ids)
+	if err != nil {
+		return nil, err
+	}
+	defer cIds.free()
+
+	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_box_visit_many(box.cBox, cIds.cArray, dataVisitor, visitorArg)
+	}
+	return box.readUsingVisitor(ctx, existingOnly, cFn)
+}
+
 // GetManyExisting reads multiple objects at once, skipping those that do not exist.
 //
 // Returns a slice of objects that should be cast to the appropriate type.
@@ -525,7 +648,7 @@ func (box *Box) GetManyExisting(ids ...uint64) (slice interface{}, err error) {
 		var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 			return C.obx_box_visit_many(box.cBox, cIds.cArray, dataVisitor, visitorArg)
 		}
-		return box.readUsingVisitor(existingOnly, cFn)
+		return box.readUsingVisitor(context.Background(), existingOnly, cFn)
 	}
 }
 
@@ -542,10 +665,24 @@ func (box *Box) GetAll() (slice interface{}, err error) {
 	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 		return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
 	}
-	return box.readUsingVisitor(existingOnly, cFn)
+	return box.readUsingVisitor(context.Background(), existingOnly, cFn)
+}
+
+// GetAllCtx is like GetAll() but reads the objects one by one, checking the given context in between.
+// The read is aborted and the context's error is returned as soon as the context is cancelled or its deadline exceeded.
+func (box *Box) GetAllCtx(ctx context.Context) (slice interface{}, err error) {
+	const existingOnly = true
+	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
+	}
+	return box.readUsingVisitor(ctx, existingOnly, cFn)
 }
 
 func (box *Box) readManyObjects(existingOnly bool, cFn func() *C.OBX_bytes_array) (slice interface{}, err error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
 	// we need a read-transaction to keep the data in dataPtr untouched (by concurrent write) until we can read it
 	// as well as making sure the relations read in binding.Load represent a consistent state
 	err = box.ObjectBox.RunInReadTx(func() error {
@@ -581,11 +718,25 @@ func (box *Box) readManyObjects(existingOnly bool, cFn func() *C.OBX_bytes_array
 	return slice, err
 }
 
-// this is a utility function to fetch objects using an obx_data_visitor
-func (box *Box) readUsingVisitor(existingOnly bool, cFn func(visitorArg unsafe.Pointer) C.obx_err) (slice interface{}, err error) {
+// this is a utility function to fetch objects using an obx_data_visitor;
+// the visit is aborted as soon as the given context is done, returning the context's error.
+func (box *Box) readUsingVisitor(ctx context.Context, existingOnly bool, cFn func(visitorArg unsafe.Pointer) C.obx_err) (slice interface{}, err error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var binding = box.entity.binding
 	var visitor uint32
 	visitor, err = dataVisitorRegister(func(bytes []byte) bool {
+		if err2 := ctx.Err(); err2 != nil {
+			err = err2
+			return false
+		}
+
 		// may be nil if an object on this index was not found (can happen with GetMany)
 		if bytes == nil {
 			if !existingOnly {
@@ -625,8 +776,79 @@ func (box *Box) readUsingVisitor(existingOnly bool, cFn func(visitorArg unsafe.P
 	}
 }
 
+// ForEach streams all stored objects to the given callback, one by one, without reading them all into memory first.
+// The iteration stops early if the callback returns false or an error; the error is passed-through as the output error.
+//
+// The callback is executed inside a read transaction; don't start a write transaction (e.g. Put) from inside it.
+// The object passed to the callback should be cast to the appropriate type.
+func (box *Box) ForEach(fn func(object interface{}) (bool, error)) error {
+	return box.ForEachCtx(context.Background(), fn)
+}
+
+// ForEachCtx is like ForEach() but aborts the iteration as soon as the given context is cancelled or its deadline
+// exceeded, returning the context's error.
+func (box *Box) ForEachCtx(ctx context.Context, fn func(object interface{}) (bool, error)) error {
+	return box.visitObjects(ctx, fn, func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
+	})
+}
+
+// visitObjects loads objects provided by the given cFn through an obx_data_visitor and passes them to the callback.
+// The visit is aborted as soon as the given context is done, returning the context's error.
+func (box *Box) visitObjects(ctx context.Context, fn func(object interface{}) (bool, error), cFn func(visitorArg unsafe.Pointer) C.obx_err) (err error) {
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	var binding = box.entity.binding
+	var visitor uint32
+	visitor, err = dataVisitorRegister(func(bytes []byte) bool {
+		if err2 := ctx.Err(); err2 != nil {
+			err = err2
+			return false
+		}
+
+		object, err2 := binding.Load(box.ObjectBox, bytes)
+		if err2 != nil {
+			err = err2
+			return false
+		}
+
+		next, err2 := fn(object)
+		if err2 != nil {
+			err = err2
+			return false
+		}
+		return next
+	})
+	if err != nil {
+		return err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	// we need a read-transaction to keep the data in dataPtr untouched (by concurrent write) until we can read it
+	// as well as making sure the relations read in binding.Load represent a consistent state
+	// use another `error` variable as `err` may be set by the visitor callback above
+	var err2 = box.ObjectBox.RunInReadTx(func() error {
+		return cCall(func() C.obx_err { return cFn(unsafe.Pointer(&visitor)) })
+	})
+
+	if err2 != nil {
+		return err2
+	}
+	return err
+}
+
 // Contains checks whether an object with the given ID is stored.
 func (box *Box) Contains(id uint64) (bool, error) {
+	if err := box.checkTx(); err != nil {
+		return false, err
+	}
+
 	var cResult C.bool
 	if err := cCall(func() C.obx_err { return C.obx_box_contains(box.cBox, C.obx_id(id), &cResult) }); err != nil {
 		return false, err
@@ -636,6 +858,10 @@ func (box *Box) Contains(id uint64) (bool, error) {
 
 // ContainsIds checks whether all of the given objects are stored in DB.
 func (box *Box) ContainsIds(ids ...uint64) (bool, error) {
+	if err := box.checkTx(); err != nil {
+		return false, err
+	}
+
 	cIds, err := goIdsArrayToC(ids)
 	if err != nil {
 		return false, err
@@ -650,6 +876,10 @@ func (box *Box) ContainsIds(ids ...uint64) (bool, error) {
 
 // RelationIds returns IDs of all target objects related to the given source object ID
 func (box *Box) RelationIds(relation *RelationToMany, sourceId uint64) ([]uint64, error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
 	targetBox, err := box.ObjectBox.box(relation.Target.Id)
 	if err != nil {
 		return nil, err
@@ -659,11 +889,45 @@ func (box *Box) RelationIds(relation *RelationToMany, sourceId uint64) ([]uint64
 	})
 }
 
+// RelationBacklinkIds returns IDs of all source objects related to the given target object ID,
+// i.e. navigates the standalone relation in the reverse direction. The box must be the one of the relation source.
+func (box *Box) RelationBacklinkIds(relation *RelationToMany, targetId uint64) ([]uint64, error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
+	if relation.Source.Id != box.entity.id {
+		return nil, fmt.Errorf("relation source entity %d doesn't match the box entity %d", relation.Source.Id, box.entity.id)
+	}
+	return cGetIds(func() *C.OBX_id_array {
+		return C.obx_box_rel_get_backlink_ids(box.cBox, C.obx_schema_id(relation.Id), C.obx_id(targetId))
+	})
+}
+
+// BacklinkIds returns IDs of all source objects pointing to the given target object ID using the relation property,
+// i.e. navigates the to-one relation in the reverse direction. The box must be the one of the relation source.
+func (box *Box) BacklinkIds(relation *RelationToOne, targetId uint64) ([]uint64, error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
+	if relation.Property.Entity.Id != box.entity.id {
+		return nil, fmt.Errorf("relation source entity %d doesn't match the box entity %d", relation.Property.Entity.Id, box.entity.id)
+	}
+	return cGetIds(func() *C.OBX_id_array {
+		return C.obx_box_get_backlink_ids(box.cBox, C.obx_schema_id(relation.Property.Id), C.obx_id(targetId))
+	})
+}
+
 // RelationReplace replaces all targets for a given source in a standalone many-to-many relation
 // It also inserts new related objects (with a 0 ID).
 func (box *Box) RelationReplace(relation *RelationToMany, sourceId uint64, sourceObject interface{},
 	targetObjects interface{}) error {
 
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
 	// get id from the object, if inserting, it would be 0 even if the argument id is already non-zero
 	// this saves us an unnecessary request to RelationIds for new objects (there can't be any relations yet)
 	id, err := box.entity.binding.GetId(sourceObject)
@@ -745,6 +1009,10 @@ func (box *Box) RelationReplace(relation *RelationToMany, sourceId uint64, sourc
 
 // RelationPut creates a relation between the given source & target objects
 func (box *Box) RelationPut(relation *RelationToMany, sourceId, targetId uint64) error {
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
 	return cCall(func() C.obx_err {
 		return C.obx_box_rel_put(box.cBox, C.obx_schema_id(relation.Id), C.obx_id(sourceId), C.obx_id(targetId))
 	})
@@ -752,7 +1020,70 @@ func (box *Box) RelationPut(relation *RelationToMany, sourceId, targetId uint64)
 
 // RelationRemove removes a relation between the given source & target objects
 func (box *Box) RelationRemove(relation *RelationToMany, sourceId, targetId uint64) error {
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
 	return cCall(func() C.obx_err {
 		return C.obx_box_rel_remove(box.cBox, C.obx_schema_id(relation.Id), C.obx_id(sourceId), C.obx_id(targetId))
 	})
 }
+
+// RelationPutMany creates relations between the given source object and all the target objects.
+// All the relations are created in a single transaction, i.e. either all or none of them are stored.
+func (box *Box) RelationPutMany(relation *RelationToMany, sourceId uint64, targetIds ...uint64) error {
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
+	return box.ObjectBox.RunInWriteTx(func() error {
+		for _, targetId := range targetIds {
+			if err := box.RelationPut(relation, sourceId, targetId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RelationRemoveMany removes relations between the given source object and all the target objects.
+// All the relations are removed in a single transaction, i.e. either all or none of them are removed.
+func (box *Box) RelationRemoveMany(relation *RelationToMany, sourceId uint64, targetIds ...uint64) error {
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
+	return box.ObjectBox.RunInWriteTx(func() error {
+		for _, targetId := range targetIds {
+			if err := box.RelationRemove(relation, sourceId, targetId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RelationIdsMany returns IDs of all target objects related to each of the given source object IDs.
+// All the IDs are read in a single transaction, i.e. they represent a consistent state.
+// Source objects without any related target are present in the result without IDs.
+func (box *Box) RelationIdsMany(relation *RelationToMany, sourceIds ...uint64) (map[uint64][]uint64, error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
+	var result = make(map[uint64][]uint64, len(sourceIds))
+	err := box.ObjectBox.RunInReadTx(func() error {
+		for _, sourceId := range sourceIds {
+			targetIds, err := box.RelationIds(relation, sourceId)
+			if err != nil {
+				return err
+			}
+			result[sourceId] = targetIds
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -24,10 +24,18 @@ import "C"
 
 import (
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"runtime"
+	"time"
 	"unsafe"
 )
 
+// the directory used by the native library if none is configured
+const defaultDirectory = "objectbox"
+
 // Builder provides tools to fully configure and construct ObjectBox
 type Builder struct {
 	model *Model
@@ -38,6 +46,30 @@ type Builder struct {
 	directory   *string
 	maxSizeInKb *uint64
 	maxReaders  *uint
+	readOnly    bool
+	fileMode    *os.FileMode
+
+	// consistency check on open
+	validatePageLimit *uint
+	validateLeafLevel bool
+
+	// async queue tuning
+	asyncMaxQueueLength        *uint
+	asyncThrottleAtQueueLength *uint
+	asyncThrottleDelay         *time.Duration
+	asyncMaxInTxDuration       *time.Duration
+	asyncMaxInTxOperations     *uint
+	asyncPreTxDelay            *time.Duration
+	asyncPostTxDelay           *time.Duration
+
+	// create the database in a new temporary directory, removed on ObjectBox.Close()
+	tempDirectory bool
+
+	// backup file to restore after opening the store
+	restoreFile *string
+
+	// data migrations executed after opening the store
+	migrations []migration
 
 	// these options are passed-through to the created ObjectBox struct
 	options
@@ -72,12 +104,7 @@ func NewBuilder() *Builder {
 			"Or check https://github.com/objectbox/objectbox-c for info about the required library.")
 	}
 
-	return &Builder{
-		options: options{
-			// defaults
-			asyncTimeout: 1000, // 1s ; TODO make this 0 to use core default?
-		},
-	}
+	return &Builder{}
 }
 
 // Directory configures the path where the database is stored
@@ -99,14 +126,104 @@ func (builder *Builder) MaxReaders(maxReaders uint) *Builder {
 	return builder
 }
 
-// asyncTimeoutTBD configures the default enqueue timeout for async operations (default is 1 second).
-// See Box.PutAsync method doc for more information.
-// TODO: implement this option in core and use it
-func (builder *Builder) asyncTimeoutTBD(milliseconds uint) *Builder {
+// TempDirectory creates the database in a new temporary directory which is removed (including all data) when the
+// ObjectBox is closed. This is useful e.g. for unit tests. Can't be combined with Directory().
+func (builder *Builder) TempDirectory() *Builder {
+	builder.tempDirectory = true
+	return builder
+}
+
+// ReadOnly opens the database in read-only mode: no schema update, no write transactions.
+// This is useful to inspect a database used by another process.
+func (builder *Builder) ReadOnly() *Builder {
+	builder.readOnly = true
+	return builder
+}
+
+// RestoreFromFile restores the database from the given backup file (see ObjectBox.Backup()) when opening it.
+// The database must be empty, e.g. a new directory; otherwise, BuildOrError() fails.
+func (builder *Builder) RestoreFromFile(path string) *Builder {
+	builder.restoreFile = &path
+	return builder
+}
+
+// FileMode sets the permissions of the database files (default: 0644).
+func (builder *Builder) FileMode(mode os.FileMode) *Builder {
+	builder.fileMode = &mode
+	return builder
+}
+
+// ValidateOnOpen enables a consistency check of the given number of database pages when the database is opened.
+// Reliable file systems already guarantee consistency, so this is primarily meant to deal with unreliable OSes, file
+// systems or hardware (e.g. cheap flash storage). A low number (e.g. 1-20) is usually sufficient and doesn't impact
+// startup performance significantly. Set leafLevel to visit leaf pages as well.
+func (builder *Builder) ValidateOnOpen(pageLimit uint, leafLevel bool) *Builder {
+	builder.validatePageLimit = &pageLimit
+	builder.validateLeafLevel = leafLevel
+	return builder
+}
+
+// AsyncTimeout configures the enqueue timeout of the default AsyncBox, i.e. Box.Async(), used when the async queue is
+// full. See NewAsyncBox() to create an AsyncBox with a different timeout.
+func (builder *Builder) AsyncTimeout(milliseconds uint) *Builder {
 	builder.asyncTimeout = milliseconds
 	return builder
 }
 
+// AsyncMaxQueueLength sets the maximum number of operations in the async queue before new ones are rejected.
+// Hitting this limit usually means the data is produced at a faster rate than it can be persisted in the background;
+// consider also AsyncMaxInTx() to optimize the throughput.
+func (builder *Builder) AsyncMaxQueueLength(length uint) *Builder {
+	builder.asyncMaxQueueLength = &length
+	return builder
+}
+
+// AsyncThrottle slows down async operation producers by the given delay on each submission once the async queue
+// reaches the given length.
+func (builder *Builder) AsyncThrottle(queueLength uint, delay time.Duration) *Builder {
+	builder.checkMicros("AsyncThrottle delay", delay)
+	builder.asyncThrottleAtQueueLength = &queueLength
+	builder.asyncThrottleDelay = &delay
+	return builder
+}
+
+// AsyncMaxInTx limits the time spent and the number of operations executed in a single async transaction before
+// a commit is enforced. This becomes relevant if the queue is constantly populated at a high rate.
+func (builder *Builder) AsyncMaxInTx(duration time.Duration, operations uint) *Builder {
+	builder.checkMicros("AsyncMaxInTx duration", duration)
+	builder.asyncMaxInTxDuration = &duration
+	builder.asyncMaxInTxOperations = &operations
+	return builder
+}
+
+// AsyncTxDelays configures how long the async queue waits before starting a transaction (giving producers some time to
+// submit more than a single operation) and after committing one (giving other transactions some time to execute).
+// Keep the values low to keep the latency low.
+func (builder *Builder) AsyncTxDelays(beforeTx, afterTx time.Duration) *Builder {
+	builder.checkMicros("AsyncTxDelays beforeTx", beforeTx)
+	builder.checkMicros("AsyncTxDelays afterTx", afterTx)
+	builder.asyncPreTxDelay = &beforeTx
+	builder.asyncPostTxDelay = &afterTx
+	return builder
+}
+
+// checkMicros sets builder.Error if the given duration doesn't fit into the native (uint32) microseconds value
+func (builder *Builder) checkMicros(name string, duration time.Duration) {
+	if duration < 0 || duration.Microseconds() > math.MaxUint32 {
+		builder.Error = fmt.Errorf("%s %v is out of range, expected 0 to %v", name, duration,
+			time.Duration(math.MaxUint32)*time.Microsecond)
+	}
+}
+
+// Logger additionally routes the debug logging enabled by ObjectBox.SetDebugFlags() (transactions, queries, async
+// queue) through the given function. The events are produced by the Go binding, with LogLevelDebug.
+// Note: the output of the native library itself can't be captured - the C API doesn't provide a logging callback, so
+// the library still writes its debug messages to the standard error output. See SlogLogger() for a log/slog adapter.
+func (builder *Builder) Logger(logger Logger) *Builder {
+	builder.logger = logger
+	return builder
+}
+
 // Model specifies schema for the database.
 //
 // Pass the result of the generated function ObjectBoxModel as an argument: Model(ObjectBoxModel())
@@ -145,6 +262,19 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		return nil, fmt.Errorf("model is not defined")
 	}
 
+	if builder.readOnly && builder.restoreFile != nil {
+		return nil, fmt.Errorf("can't restore a backup into a read-only database")
+	}
+
+	if builder.tempDirectory && builder.directory != nil {
+		return nil, fmt.Errorf("Directory() and TempDirectory() can't be used at the same time")
+	}
+
+	// validated against the model only, before the database (directory) is created by obx_store_open()
+	if err := linkRemovePolicies(builder.model.entitiesById, builder.model.entitiesByName); err != nil {
+		return nil, err
+	}
+
 	// for native calls/createError()
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
@@ -154,8 +284,32 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		return nil, createError()
 	}
 
+	var directory = defaultDirectory
 	if builder.directory != nil {
-		cDir := C.CString(*builder.directory)
+		directory = *builder.directory
+	} else if builder.tempDirectory {
+		var err error
+		if directory, err = ioutil.TempDir("", "objectbox"); err != nil {
+			C.obx_opt_free(cOptions)
+			return nil, fmt.Errorf("can't create a temporary directory: %v", err)
+		}
+	}
+
+	// removes the temporary directory if the store couldn't be opened
+	var success = false
+	defer func() {
+		if !success && builder.tempDirectory {
+			os.RemoveAll(directory)
+		}
+	}()
+
+	// used to decide whether migrations need to run; checked before the file is created by obx_store_open().
+	// A restored database keeps the schema version of the backup, thus it's not considered new.
+	_, err := os.Stat(filepath.Join(directory, dataFileName))
+	var newDatabase = os.IsNotExist(err) && builder.restoreFile == nil
+
+	if builder.directory != nil || builder.tempDirectory {
+		cDir := C.CString(directory)
 		defer C.free(unsafe.Pointer(cDir))
 		if 0 != C.obx_opt_directory(cOptions, cDir) {
 			C.obx_opt_free(cOptions)
@@ -171,6 +325,41 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		C.obx_opt_max_readers(cOptions, C.uint(*builder.maxReaders))
 	}
 
+	if builder.readOnly {
+		C.obx_opt_read_only(cOptions, true)
+	}
+
+	if builder.fileMode != nil {
+		C.obx_opt_file_mode(cOptions, C.uint(builder.fileMode.Perm()))
+	}
+
+	if builder.validatePageLimit != nil {
+		C.obx_opt_validate_on_open(cOptions, C.size_t(*builder.validatePageLimit), C.bool(builder.validateLeafLevel))
+	}
+
+	if builder.asyncMaxQueueLength != nil {
+		C.obx_opt_async_max_queue_length(cOptions, C.size_t(*builder.asyncMaxQueueLength))
+	}
+
+	if builder.asyncThrottleAtQueueLength != nil {
+		C.obx_opt_async_throttle_at_queue_length(cOptions, C.size_t(*builder.asyncThrottleAtQueueLength))
+		C.obx_opt_async_throttle_micros(cOptions, C.uint32_t(builder.asyncThrottleDelay.Microseconds()))
+	}
+
+	if builder.asyncMaxInTxDuration != nil {
+		C.obx_opt_async_max_in_tx_duration(cOptions, C.uint32_t(builder.asyncMaxInTxDuration.Microseconds()))
+		C.obx_opt_async_max_in_tx_operations(cOptions, C.uint32_t(*builder.asyncMaxInTxOperations))
+	}
+
+	if builder.asyncPreTxDelay != nil {
+		C.obx_opt_async_pre_txn_delay(cOptions, C.uint32_t(builder.asyncPreTxDelay.Microseconds()))
+		C.obx_opt_async_post_txn_delay(cOptions, C.uint32_t(builder.asyncPostTxDelay.Microseconds()))
+	}
+
+	if err := builder.model.addSchemaEntity(); err != nil {
+		C.obx_opt_free(cOptions)
+		return nil, err
+	}
 	C.obx_opt_model(cOptions, builder.model.cModel)
 
 	// cOptions is consumed by obx_store_open() so no need to free it
@@ -185,10 +374,43 @@ func (builder *Builder) BuildOrError() (*ObjectBox, error) {
 		entitiesByName: builder.model.entitiesByName,
 		boxes:          make(map[TypeId]*Box, len(builder.model.entitiesById)),
 		options:        builder.options,
+		directory:      directory,
+		stats:          &statsCounters{},
+		maxSizeInKb:    defaultMaxSizeInKb,
+	}
+	success = true
+
+	if builder.maxSizeInKb != nil {
+		ob.maxSizeInKb = *builder.maxSizeInKb
+	}
+
+	if builder.tempDirectory {
+		ob.removeDirectoryOnClose = true
+	}
+
+	if ob.schemaBox = C.obx_box(cStore, C.obx_schema_id(schemaEntityId)); ob.schemaBox == nil {
+		var err = createError()
+		ob.Close()
+		return nil, err
 	}
 
 	for _, entity := range builder.model.entitiesById {
 		entity.objectBox = ob
 	}
+
+	if builder.restoreFile != nil {
+		if err := ob.restoreFromFile(*builder.restoreFile); err != nil {
+			ob.Close()
+			return nil, fmt.Errorf("failed to restore the backup %s: %v", *builder.restoreFile, err)
+		}
+	}
+
+	if len(builder.migrations) > 0 {
+		if err := ob.migrate(builder, newDatabase); err != nil {
+			ob.Close()
+			return nil, err
+		}
+	}
+
 	return ob, nil
 }
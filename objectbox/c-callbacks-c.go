@@ -22,6 +22,7 @@ package objectbox
 
 /*
 #include <stdbool.h>
+#include <stddef.h>
 #include <stdint.h>
 */
 import "C"
@@ -62,3 +63,11 @@ func cVoidConstVoidCallbackDispatch(callbackIdPtr unsafe.Pointer, arg unsafe.Poi
 		callback.callVoidConstVoid(arg)
 	}
 }
+
+//export cVoidConstVoidSizeCallbackDispatch
+func cVoidConstVoidSizeCallbackDispatch(callbackIdPtr unsafe.Pointer, arg unsafe.Pointer, size C.size_t) {
+	var callback = cCallbackLookup(callbackIdPtr)
+	if callback != nil {
+		callback.callVoidConstVoidSize(arg, uint(size))
+	}
+}
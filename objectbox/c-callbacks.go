@@ -59,6 +59,10 @@ typedef void cVoidInt64Callback(void* callbackId, int64_t arg);
 // void return, const void* argument
 extern void cVoidConstVoidCallbackDispatch(void* callbackId);
 typedef void cVoidConstVoidCallback(void* callbackId, const void* arg);
+
+// void return, const void* and size_t arguments
+extern void cVoidConstVoidSizeCallbackDispatch(void* callbackId);
+typedef void cVoidConstVoidSizeCallback(void* callbackId, const void* arg, size_t size);
 */
 import "C"
 import (
@@ -75,6 +79,7 @@ type cCallable interface {
 	callVoidUint64(uint64)
 	callVoidInt64(int64)
 	callVoidConstVoid(unsafe.Pointer)
+	callVoidConstVoidSize(unsafe.Pointer, uint)
 }
 
 // programming error - using an incorrect `cCallable` (arguments and return-type combination)
@@ -82,28 +87,31 @@ const cCallablePanicMsg = "invalid callback signature"
 
 type cVoidCallback func()
 
-func (fn cVoidCallback) callVoid()                        { fn() }
-func (fn cVoidCallback) callVoidUint64(uint64)            { panic(cCallablePanicMsg) }
-func (fn cVoidCallback) callVoidInt64(int64)              { panic(cCallablePanicMsg) }
-func (fn cVoidCallback) callVoidConstVoid(unsafe.Pointer) { panic(cCallablePanicMsg) }
+func (fn cVoidCallback) callVoid()                                  { fn() }
+func (fn cVoidCallback) callVoidUint64(uint64)                      { panic(cCallablePanicMsg) }
+func (fn cVoidCallback) callVoidInt64(int64)                        { panic(cCallablePanicMsg) }
+func (fn cVoidCallback) callVoidConstVoid(unsafe.Pointer)           { panic(cCallablePanicMsg) }
+func (fn cVoidCallback) callVoidConstVoidSize(unsafe.Pointer, uint) { panic(cCallablePanicMsg) }
 
 var cVoidCallbackDispatchPtr = (*C.cVoidCallback)(unsafe.Pointer(C.cVoidCallbackDispatch))
 
 type cVoidUint64Callback func(uint64)
 
-func (fn cVoidUint64Callback) callVoid()                        { panic(cCallablePanicMsg) }
-func (fn cVoidUint64Callback) callVoidUint64(arg uint64)        { fn(arg) }
-func (fn cVoidUint64Callback) callVoidInt64(int64)              { panic(cCallablePanicMsg) }
-func (fn cVoidUint64Callback) callVoidConstVoid(unsafe.Pointer) { panic(cCallablePanicMsg) }
+func (fn cVoidUint64Callback) callVoid()                                  { panic(cCallablePanicMsg) }
+func (fn cVoidUint64Callback) callVoidUint64(arg uint64)                  { fn(arg) }
+func (fn cVoidUint64Callback) callVoidInt64(int64)                        { panic(cCallablePanicMsg) }
+func (fn cVoidUint64Callback) callVoidConstVoid(unsafe.Pointer)           { panic(cCallablePanicMsg) }
+func (fn cVoidUint64Callback) callVoidConstVoidSize(unsafe.Pointer, uint) { panic(cCallablePanicMsg) }
 
 var cVoidUint64CallbackDispatchPtr = (*C.cVoidUint64Callback)(unsafe.Pointer(C.cVoidUint64CallbackDispatch))
 
 type cVoidInt64Callback func(int64)
 
-func (fn cVoidInt64Callback) callVoid()                        { panic(cCallablePanicMsg) }
-func (fn cVoidInt64Callback) callVoidUint64(uint64)            { panic(cCallablePanicMsg) }
-func (fn cVoidInt64Callback) callVoidInt64(arg int64)          { fn(arg) }
-func (fn cVoidInt64Callback) callVoidConstVoid(unsafe.Pointer) { panic(cCallablePanicMsg) }
+func (fn cVoidInt64Callback) callVoid()                                  { panic(cCallablePanicMsg) }
+func (fn cVoidInt64Callback) callVoidUint64(uint64)                      { panic(cCallablePanicMsg) }
+func (fn cVoidInt64Callback) callVoidInt64(arg int64)                    { fn(arg) }
+func (fn cVoidInt64Callback) callVoidConstVoid(unsafe.Pointer)           { panic(cCallablePanicMsg) }
+func (fn cVoidInt64Callback) callVoidConstVoidSize(unsafe.Pointer, uint) { panic(cCallablePanicMsg) }
 
 var cVoidInt64CallbackDispatchPtr = (*C.cVoidInt64Callback)(unsafe.Pointer(C.cVoidInt64CallbackDispatch))
 
@@ -113,9 +121,24 @@ func (fn cVoidConstVoidCallback) callVoid()                            { panic(c
 func (fn cVoidConstVoidCallback) callVoidUint64(uint64)                { panic(cCallablePanicMsg) }
 func (fn cVoidConstVoidCallback) callVoidInt64(int64)                  { panic(cCallablePanicMsg) }
 func (fn cVoidConstVoidCallback) callVoidConstVoid(arg unsafe.Pointer) { fn(arg) }
+func (fn cVoidConstVoidCallback) callVoidConstVoidSize(unsafe.Pointer, uint) {
+	panic(cCallablePanicMsg)
+}
 
 var cVoidConstVoidCallbackDispatchPtr = (*C.cVoidConstVoidCallback)(unsafe.Pointer(C.cVoidConstVoidCallbackDispatch))
 
+type cVoidConstVoidSizeCallback func(unsafe.Pointer, uint)
+
+func (fn cVoidConstVoidSizeCallback) callVoid()                        { panic(cCallablePanicMsg) }
+func (fn cVoidConstVoidSizeCallback) callVoidUint64(uint64)            { panic(cCallablePanicMsg) }
+func (fn cVoidConstVoidSizeCallback) callVoidInt64(int64)              { panic(cCallablePanicMsg) }
+func (fn cVoidConstVoidSizeCallback) callVoidConstVoid(unsafe.Pointer) { panic(cCallablePanicMsg) }
+func (fn cVoidConstVoidSizeCallback) callVoidConstVoidSize(arg unsafe.Pointer, size uint) {
+	fn(arg, size)
+}
+
+var cVoidConstVoidSizeCallbackDispatchPtr = (*C.cVoidConstVoidSizeCallback)(unsafe.Pointer(C.cVoidConstVoidSizeCallbackDispatch))
+
 type cCallbackId uintptr
 
 var cCallbackLastId cCallbackId
@@ -0,0 +1,340 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-generator/cmd/objectbox-gogen"
+)
+
+// DynamicBox provides access to objects of an entity as map[string]interface{}, keyed by property names, without the
+// generated binding code. This is useful for generic tools, e.g. admin dashboards.
+//
+// All Box methods are available, working with maps instead of structs: Get() returns map[string]interface{}, GetAll()
+// and Query.Find() return []map[string]interface{} and Put() accepts a map. Values are read as the Go type matching
+// the property type, e.g. int32 for an Int property or uint64 for a to-one relation (the target object ID); unset
+// values are nil. When writing, any Go number type is accepted as long as the value fits into the property type.
+// Missing (or nil) values are left unset; unknown properties cause an error.
+//
+// Standalone relations are not part of the map, use Box.RelationIds() & co. instead.
+type DynamicBox struct {
+	*Box
+}
+
+// DynamicBox returns a box for the entity with the given name, working with maps instead of structs
+func (ob *ObjectBox) DynamicBox(entityName string) (*DynamicBox, error) {
+	var entity = ob.entitiesByName[entityName]
+	if entity == nil {
+		return nil, fmt.Errorf("entity %s not found in the model", entityName)
+	}
+
+	box, err := ob.box(entity.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.idProperty() == nil {
+		return nil, fmt.Errorf("entity %s doesn't have an ID property", entityName)
+	}
+
+	// a copy of the entity using the dynamic binding; related objects are not handled, only their IDs
+	var dynamicEntity = *entity
+	dynamicEntity.binding = &dynamicBinding{entity: &dynamicEntity}
+	dynamicEntity.hasRelations = false
+
+	var dynamicBox = &Box{
+		ObjectBox: ob,
+		entity:    &dynamicEntity,
+		cBox:      box.cBox,
+	}
+	dynamicBox.async = &AsyncBox{
+		box:    dynamicBox,
+		cAsync: box.async.cAsync,
+		cOwned: false,
+	}
+
+	return &DynamicBox{dynamicBox}, nil
+}
+
+// Get reads a single object; returns nil (and no error) if the object doesn't exist
+func (box *DynamicBox) Get(id uint64) (map[string]interface{}, error) {
+	object, err := box.Box.Get(id)
+	if object == nil || err != nil {
+		return nil, err
+	}
+	return object.(map[string]interface{}), nil
+}
+
+// GetAll reads all stored objects
+func (box *DynamicBox) GetAll() ([]map[string]interface{}, error) {
+	objects, err := box.Box.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return objects.([]map[string]interface{}), nil
+}
+
+// Property returns the property with the given name, to be used in query conditions by wrapping it in the matching
+// property type, for example:
+// 		name, err := box.Property("Name")
+// 		...
+// 		box.Query(objectbox.PropertyString{BaseProperty: name}.Equals("Joe", true))
+func (box *DynamicBox) Property(name string) (*BaseProperty, error) {
+	for _, property := range box.entity.properties {
+		if property.Name == name {
+			return &BaseProperty{
+				Id:     property.Id,
+				Entity: &Entity{Id: box.entity.id},
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("property %s not found on entity %s", name, box.entity.name)
+}
+
+// dynamicBinding implements ObjectBinding for map[string]interface{} based on the model information
+type dynamicBinding struct {
+	entity *entity
+}
+
+func (binding *dynamicBinding) AddToModel(model *Model) {
+	model.Error = fmt.Errorf("dynamic binding of %s can't be added to a model", binding.entity.name)
+}
+
+func (binding *dynamicBinding) object(object interface{}) (map[string]interface{}, error) {
+	if values, ok := object.(map[string]interface{}); ok {
+		return values, nil
+	}
+	return nil, fmt.Errorf("expected map[string]interface{} as a dynamic %s object, got %T", binding.entity.name, object)
+}
+
+func (binding *dynamicBinding) GetId(object interface{}) (uint64, error) {
+	values, err := binding.object(object)
+	if err != nil {
+		return 0, err
+	}
+
+	var idProperty = binding.entity.idProperty()
+	id, err := convertValue(idProperty, values[idProperty.Name])
+	if err != nil || id == nil {
+		return 0, err
+	}
+	return id.(uint64), nil
+}
+
+func (binding *dynamicBinding) SetId(object interface{}, id uint64) error {
+	values, err := binding.object(object)
+	if err != nil {
+		return err
+	}
+	values[binding.entity.idProperty().Name] = id
+	return nil
+}
+
+func (binding *dynamicBinding) PutRelated(ob *ObjectBox, object interface{}, id uint64) error {
+	return nil
+}
+
+func (binding *dynamicBinding) Flatten(object interface{}, fbb *flatbuffers.Builder, id uint64) error {
+	values, err := binding.object(object)
+	if err != nil {
+		return err
+	}
+
+	var propertiesByName = make(map[string]*PropertyInfo, len(binding.entity.properties))
+	for _, property := range binding.entity.properties {
+		propertiesByName[property.Name] = property
+	}
+
+	var converted = make(map[*PropertyInfo]interface{}, len(values))
+	for name, value := range values {
+		var property = propertiesByName[name]
+		if property == nil {
+			return fmt.Errorf("unknown property %s on entity %s", name, binding.entity.name)
+		}
+
+		if value, err = convertValue(property, value); err != nil {
+			return fmt.Errorf("property %s: %v", name, err)
+		} else if value != nil {
+			converted[property] = value
+		}
+	}
+	converted[binding.entity.idProperty()] = id
+
+	return startObjectWithValues(fbb, converted, binding.entity.maxPropertyId())
+}
+
+func (binding *dynamicBinding) Load(ob *ObjectBox, bytes []byte) (interface{}, error) {
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+
+	var values = make(map[string]interface{}, len(binding.entity.properties))
+	for _, property := range binding.entity.properties {
+		values[property.Name] = property.readFlatBuffers(table)
+	}
+	return values, nil
+}
+
+func (binding *dynamicBinding) MakeSlice(capacity int) interface{} {
+	return make([]map[string]interface{}, 0, capacity)
+}
+
+func (binding *dynamicBinding) AppendToSlice(slice interface{}, object interface{}) interface{} {
+	if object == nil {
+		return append(slice.([]map[string]interface{}), nil)
+	}
+	return append(slice.([]map[string]interface{}), object.(map[string]interface{}))
+}
+
+func (binding *dynamicBinding) GeneratorVersion() int {
+	return gogen.VersionId
+}
+
+// isDynamicPropertyType returns true if the dynamic binding can read and write values of the given type
+func isDynamicPropertyType(propertyType PropertyType) bool {
+	switch propertyType {
+	case PropertyTypeBool, PropertyTypeByte, PropertyTypeChar, PropertyTypeShort, PropertyTypeInt, PropertyTypeLong,
+		PropertyTypeDate, PropertyTypeDateNano, PropertyTypeRelation, PropertyTypeFloat, PropertyTypeDouble,
+		PropertyTypeString, PropertyTypeByteVector, PropertyTypeStringVector:
+		return true
+	default:
+		return false
+	}
+}
+
+// convertValue converts the given value to the Go type matching the property, as returned by readFlatBuffers()
+func convertValue(property *PropertyInfo, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var unsigned = property.Flags&PropertyFlagUnsigned != 0
+	switch property.Type {
+	case PropertyTypeBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case PropertyTypeByte, PropertyTypeChar:
+		if unsigned {
+			v, err := convertUint(value, math.MaxUint8)
+			return uint8(v), err
+		}
+		v, err := convertInt(value, math.MinInt8, math.MaxInt8)
+		return int8(v), err
+	case PropertyTypeShort:
+		if unsigned {
+			v, err := convertUint(value, math.MaxUint16)
+			return uint16(v), err
+		}
+		v, err := convertInt(value, math.MinInt16, math.MaxInt16)
+		return int16(v), err
+	case PropertyTypeInt:
+		if unsigned {
+			v, err := convertUint(value, math.MaxUint32)
+			return uint32(v), err
+		}
+		v, err := convertInt(value, math.MinInt32, math.MaxInt32)
+		return int32(v), err
+	case PropertyTypeLong, PropertyTypeDate, PropertyTypeDateNano, PropertyTypeRelation:
+		if unsigned || property.Flags&PropertyFlagId != 0 || property.Type == PropertyTypeRelation {
+			return convertUint(value, math.MaxUint64)
+		}
+		return convertInt(value, math.MinInt64, math.MaxInt64)
+	case PropertyTypeFloat:
+		v, err := convertFloat(value)
+		return float32(v), err
+	case PropertyTypeDouble:
+		return convertFloat(value)
+	case PropertyTypeString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case PropertyTypeByteVector:
+		if v, ok := value.([]byte); ok {
+			return v, nil
+		}
+	case PropertyTypeStringVector:
+		if v, ok := value.([]string); ok {
+			return v, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported property type %v", property.Type)
+	}
+	return nil, fmt.Errorf("can't use %T as a %v value", value, property.Type)
+}
+
+func convertInt(value interface{}, min, max int64) (int64, error) {
+	var v = reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() >= min && v.Int() <= max {
+			return v.Int(), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() <= uint64(max) {
+			return int64(v.Uint()), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		// e.g. numbers decoded by encoding/json; -min is max+1, exactly representable unlike e.g. MaxInt64
+		if f := v.Float(); f == math.Trunc(f) && f >= float64(min) && f < -float64(min) {
+			return int64(f), nil
+		}
+	default:
+		return 0, fmt.Errorf("can't use %T as an integer value", value)
+	}
+	return 0, fmt.Errorf("value %v out of range [%d, %d]", value, min, max)
+}
+
+func convertUint(value interface{}, max uint64) (uint64, error) {
+	var v = reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() >= 0 && uint64(v.Int()) <= max {
+			return uint64(v.Int()), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() <= max {
+			return v.Uint(), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		// e.g. numbers decoded by encoding/json
+		if f := v.Float(); f == math.Trunc(f) && f >= 0 && f < float64(max)+1 {
+			return uint64(f), nil
+		}
+	default:
+		return 0, fmt.Errorf("can't use %T as an unsigned integer value", value)
+	}
+	return 0, fmt.Errorf("value %v out of range [0, %d]", value, max)
+}
+
+func convertFloat(value interface{}) (float64, error) {
+	var v = reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+	return 0, fmt.Errorf("can't use %T as a floating point value", value)
+}
@@ -31,4 +31,52 @@ type entity struct {
 
 	// whether this entity has any relations (standalone or property-rels) - configured during model creation
 	hasRelations bool
+
+	// standalone (many-to-many) relations with this entity as the source - configured during model creation
+	relations []RelationInfo
+
+	// properties in the order of registration - configured during model creation
+	properties []*PropertyInfo
+
+	// relations from other entities (or this one) pointing to this entity and having a remove policy
+	incomingRelations []incomingRelation
+}
+
+// idProperty returns the ID property or nil if it hasn't been registered (invalid binding)
+func (entity *entity) idProperty() *PropertyInfo {
+	for _, property := range entity.properties {
+		if property.Flags&PropertyFlagId != 0 {
+			return property
+		}
+	}
+	return nil
+}
+
+// property returns the property with the given ID or nil if it hasn't been registered (invalid binding)
+func (entity *entity) property(id TypeId) *PropertyInfo {
+	for _, property := range entity.properties {
+		if property.Id == id {
+			return property
+		}
+	}
+	return nil
+}
+
+// lastProperty returns the property registered most recently
+func (entity *entity) lastProperty() *PropertyInfo {
+	if len(entity.properties) == 0 {
+		return nil
+	}
+	return entity.properties[len(entity.properties)-1]
+}
+
+// maxPropertyId returns the highest ID of all properties
+func (entity *entity) maxPropertyId() TypeId {
+	var result TypeId
+	for _, property := range entity.properties {
+		if result < property.Id {
+			result = property.Id
+		}
+	}
+	return result
 }
@@ -0,0 +1,674 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"unsafe"
+
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+)
+
+// PutMode defines how objects are written if an object with the same ID does or doesn't exist, see Box.ImportJSON()
+type PutMode int
+
+const (
+	// PutModePut is the standard put ("insert or update")
+	PutModePut PutMode = cPutModePut
+
+	// PutModeInsert succeeds only if the object does not exist yet
+	PutModeInsert PutMode = cPutModeInsert
+
+	// PutModeUpdate succeeds only if the object already exists
+	PutModeUpdate PutMode = cPutModeUpdate
+)
+
+// ExportJSON writes all objects stored in the box to the given writer, as a JSON array of objects.
+// Each object is written as a JSON object keyed by property names, based on the model, i.e. no Go structs are needed
+// to read the output. To-one relations are written as target object IDs, unset (nil) values as null and byte vectors
+// as base64 strings (standard encoding/json format). Floating-point values not representable in JSON are written as
+// strings "NaN", "Infinity" and "-Infinity", which are also accepted by ImportJSON().
+// Standalone (many-to-many) relations are written under the "relations" key, as target object IDs keyed by the
+// relation ID, e.g. "relations": {"4": [1, 2]}.
+// All objects are read inside a single read transaction.
+func (box *Box) ExportJSON(w io.Writer) error {
+	return box.exportJSON(w, func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_box_visit_all(box.cBox, dataVisitor, visitorArg)
+	})
+}
+
+// ExportJSON writes all objects matching the query to the given writer, in the same format as Box.ExportJSON().
+func (query *Query) ExportJSON(w io.Writer) error {
+	defer runtime.KeepAlive(query)
+
+	if err := query.check(); err != nil {
+		return err
+	}
+
+	return query.box.exportJSON(w, func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
+	})
+}
+
+// exportJSON writes objects provided by the given cFn through an obx_data_visitor
+func (box *Box) exportJSON(w io.Writer, cFn func(visitorArg unsafe.Pointer) C.obx_err) error {
+	if err := box.checkTx(); err != nil {
+		return err
+	}
+
+	var out = bufio.NewWriter(w)
+	var properties = box.entity.properties
+	var idProperty = box.entity.idProperty()
+
+	// fail right away instead of writing the values of unsupported properties as null
+	for _, property := range properties {
+		if !property.supportsJSON() {
+			return fmt.Errorf("can't export property %s.%s: unsupported property type %d",
+				box.entity.name, property.Name, property.Type)
+		}
+	}
+
+	targetBoxes, err := box.jsonRelationTargetBoxes()
+	if err != nil {
+		return err
+	} else if len(targetBoxes) > 0 && idProperty == nil {
+		return fmt.Errorf("entity %s doesn't have an ID property", box.entity.name)
+	}
+
+	if _, err := out.WriteString("["); err != nil {
+		return err
+	}
+
+	var first = true
+	var writeObject = func(bytes []byte) error {
+		var table = &flatbuffers.Table{
+			Bytes: bytes,
+			Pos:   flatbuffers.GetUOffsetT(bytes),
+		}
+
+		if first {
+			first = false
+			if _, err := out.WriteString("\n{"); err != nil {
+				return err
+			}
+		} else if _, err := out.WriteString(",\n{"); err != nil {
+			return err
+		}
+
+		for i, property := range properties {
+			if i > 0 {
+				if err := out.WriteByte(','); err != nil {
+					return err
+				}
+			}
+
+			name, err := json.Marshal(property.Name)
+			if err != nil {
+				return err
+			}
+
+			value, err := marshalJSONValue(property.readFlatBuffers(table))
+			if err != nil {
+				return fmt.Errorf("property %s: %v", property.Name, err)
+			}
+
+			if _, err = out.Write(name); err != nil {
+				return err
+			}
+			if err = out.WriteByte(':'); err != nil {
+				return err
+			}
+			if _, err = out.Write(value); err != nil {
+				return err
+			}
+		}
+
+		if len(targetBoxes) > 0 {
+			id, ok := idProperty.readFlatBuffers(table).(uint64)
+			if !ok {
+				return fmt.Errorf("object of entity %s doesn't have a valid ID", box.entity.name)
+			}
+			if err := box.writeJSONRelations(out, targetBoxes, id); err != nil {
+				return err
+			}
+		}
+
+		return out.WriteByte('}')
+	}
+
+	var visitor uint32
+	visitor, err = dataVisitorRegister(func(bytes []byte) bool {
+		err = writeObject(bytes)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	defer dataVisitorUnregister(visitor)
+
+	// use another `error` variable as `err` may be set by the visitor callback above
+	var err2 = box.ObjectBox.RunInReadTx(func() error {
+		return cCall(func() C.obx_err { return cFn(unsafe.Pointer(&visitor)) })
+	})
+
+	if err2 != nil {
+		return err2
+	} else if err != nil {
+		return err
+	}
+
+	if _, err = out.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// jsonRelationsKey holds standalone relations of an object in the JSON format, see ExportJSON()
+const jsonRelationsKey = "relations"
+
+// jsonRelationTargetBoxes returns target boxes of all standalone relations of this box's entity, by relation ID
+func (box *Box) jsonRelationTargetBoxes() (map[TypeId]*Box, error) {
+	if len(box.entity.relations) == 0 {
+		return nil, nil
+	}
+
+	for _, property := range box.entity.properties {
+		if property.Name == jsonRelationsKey {
+			return nil, fmt.Errorf("entity %s has a property named %s which clashes with standalone relations",
+				box.entity.name, jsonRelationsKey)
+		}
+	}
+
+	var targetBoxes = make(map[TypeId]*Box, len(box.entity.relations))
+	for _, relation := range box.entity.relations {
+		targetBox, err := box.ObjectBox.box(relation.TargetEntityId)
+		if err != nil {
+			return nil, err
+		}
+		targetBoxes[relation.Id] = targetBox
+	}
+	return targetBoxes, nil
+}
+
+// writeJSONRelations writes the "relations" key of the given source object; must be called inside a read transaction
+func (box *Box) writeJSONRelations(out *bufio.Writer, targetBoxes map[TypeId]*Box, sourceId uint64) error {
+	var relations = make(map[string][]uint64, len(box.entity.relations))
+	for _, relation := range box.entity.relations {
+		var targetBox = targetBoxes[relation.Id]
+		targetIds, err := cGetIds(func() *C.OBX_id_array {
+			return C.obx_box_rel_get_ids(targetBox.cBox, C.obx_schema_id(relation.Id), C.obx_id(sourceId))
+		})
+		if err != nil {
+			return err
+		}
+		relations[fmt.Sprint(relation.Id)] = targetIds
+	}
+
+	// encoding/json writes map keys sorted, i.e. the output is stable
+	value, err := json.Marshal(relations)
+	if err != nil {
+		return err
+	}
+
+	if _, err = out.WriteString(`,"` + jsonRelationsKey + `":`); err != nil {
+		return err
+	}
+	_, err = out.Write(value)
+	return err
+}
+
+// readJSONRelations replaces standalone relations of the given source object with the given ones (from the
+// "relations" key); must be called inside a write transaction
+func (box *Box) readJSONRelations(targetBoxes map[TypeId]*Box, sourceId uint64, raw json.RawMessage) error {
+	var relations map[string][]uint64
+	if err := json.Unmarshal(raw, &relations); err != nil {
+		return fmt.Errorf("%s: %v", jsonRelationsKey, err)
+	}
+
+	for key, targetIds := range relations {
+		var relationId TypeId
+		if _, err := fmt.Sscan(key, &relationId); err != nil || targetBoxes[relationId] == nil {
+			return fmt.Errorf("unknown standalone relation %s on entity %s", key, box.entity.name)
+		}
+		var targetBox = targetBoxes[relationId]
+
+		existingIds, err := cGetIds(func() *C.OBX_id_array {
+			return C.obx_box_rel_get_ids(targetBox.cBox, C.obx_schema_id(relationId), C.obx_id(sourceId))
+		})
+		if err != nil {
+			return err
+		}
+
+		var keep = make(map[uint64]bool, len(targetIds))
+		for _, targetId := range targetIds {
+			keep[targetId] = true
+		}
+
+		for _, targetId := range existingIds {
+			if keep[targetId] {
+				delete(keep, targetId)
+			} else if err := cCall(func() C.obx_err {
+				return C.obx_box_rel_remove(box.cBox, C.obx_schema_id(relationId), C.obx_id(sourceId), C.obx_id(targetId))
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, targetId := range targetIds {
+			if !keep[targetId] {
+				continue
+			}
+			delete(keep, targetId) // duplicates in the input
+			if err := cCall(func() C.obx_err {
+				return C.obx_box_rel_put(box.cBox, C.obx_schema_id(relationId), C.obx_id(sourceId), C.obx_id(targetId))
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ImportJSON reads a JSON array of objects, in the format written by ExportJSON(), and writes them to the box using
+// the given mode. Objects without an ID (missing, null or 0) are inserted as new objects. Properties missing in the
+// input (or null) are left unset; unknown properties cause an error. Standalone relations given in the input replace
+// the existing ones of the object, those missing in the input are left untouched.
+// All objects are written inside a single write transaction, i.e. either all or none of them are imported.
+//
+// Returns: IDs of the imported objects (in the same order as in the input).
+func (box *Box) ImportJSON(r io.Reader, mode PutMode) (ids []uint64, err error) {
+	if err := box.checkTx(); err != nil {
+		return nil, err
+	}
+
+	if mode != PutModePut && mode != PutModeInsert && mode != PutModeUpdate {
+		return nil, fmt.Errorf("invalid put mode %d", mode)
+	}
+
+	var idProperty = box.entity.idProperty()
+	if idProperty == nil {
+		return nil, fmt.Errorf("entity %s doesn't have an ID property", box.entity.name)
+	}
+
+	var propertiesByName = make(map[string]*PropertyInfo, len(box.entity.properties))
+	for _, property := range box.entity.properties {
+		propertiesByName[property.Name] = property
+	}
+	var lastPropertyId = box.entity.maxPropertyId()
+
+	targetBoxes, err := box.jsonRelationTargetBoxes()
+	if err != nil {
+		return nil, err
+	}
+
+	var decoder = json.NewDecoder(r)
+
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("invalid JSON: expected an array of objects")
+	}
+
+	err = box.ObjectBox.RunInWriteTx(func() error {
+		var fbb = flatbuffers.NewBuilder(512)
+		var reservedUpTo uint64
+		for decoder.More() {
+			var object map[string]json.RawMessage
+			if err := decoder.Decode(&object); err != nil {
+				return err
+			}
+
+			var relations json.RawMessage
+			if len(targetBoxes) > 0 {
+				relations = object[jsonRelationsKey]
+				delete(object, jsonRelationsKey)
+			}
+
+			var values = make(map[*PropertyInfo]interface{}, len(object))
+			for name, raw := range object {
+				var property = propertiesByName[name]
+				if property == nil {
+					return fmt.Errorf("unknown property %s on entity %s", name, box.entity.name)
+				}
+
+				value, err := property.parseJSON(raw)
+				if err != nil {
+					return fmt.Errorf("property %s: %v", name, err)
+				}
+				if value != nil {
+					values[property] = value
+				}
+			}
+
+			var id uint64
+			if value, ok := values[idProperty]; ok {
+				id = value.(uint64)
+			}
+
+			if mode == PutModeUpdate {
+				if id == 0 {
+					return errors.New("cannot update an object with ID 0 - if it's a new object use Put or Insert instead")
+				}
+			} else if id == 0 {
+				var err error
+				if id, err = box.idForPut(0); err != nil {
+					return err
+				}
+			} else {
+				// objects coming from another store may have IDs this store's ID sequence hasn't reached yet
+				var err error
+				if reservedUpTo, err = box.reserveIdsUpTo(id, reservedUpTo); err != nil {
+					return err
+				}
+			}
+			values[idProperty] = id
+
+			fbb.Reset()
+			if err := flattenValues(fbb, values, lastPropertyId); err != nil {
+				return err
+			}
+			var bytes = fbb.FinishedBytes()
+
+			if err := cCall(func() C.obx_err {
+				return C.obx_box_put5(box.cBox, C.obx_id(id), unsafe.Pointer(&bytes[0]), C.size_t(len(bytes)), C.OBXPutMode(mode))
+			}); err != nil {
+				return err
+			}
+
+			if relations != nil && string(relations) != "null" {
+				if err := box.readJSONRelations(targetBoxes, id, relations); err != nil {
+					return err
+				}
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+
+	if err == nil {
+		if _, err = decoder.Token(); err != nil {
+			err = fmt.Errorf("invalid JSON: %v", err)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// readFlatBuffers reads the value of this property; returns nil if the value is not present
+func (property *PropertyInfo) readFlatBuffers(table *flatbuffers.Table) interface{} {
+	var slot = flatbuffers.VOffsetT(4 + 2*(property.Id-1))
+	if table.Offset(slot) == 0 {
+		return nil
+	}
+
+	var unsigned = property.Flags&PropertyFlagUnsigned != 0
+	switch property.Type {
+	case PropertyTypeBool:
+		return fbutils.GetBoolSlot(table, slot)
+	case PropertyTypeByte, PropertyTypeChar:
+		if unsigned {
+			return fbutils.GetUint8Slot(table, slot)
+		}
+		return fbutils.GetInt8Slot(table, slot)
+	case PropertyTypeShort:
+		if unsigned {
+			return fbutils.GetUint16Slot(table, slot)
+		}
+		return fbutils.GetInt16Slot(table, slot)
+	case PropertyTypeInt:
+		if unsigned {
+			return fbutils.GetUint32Slot(table, slot)
+		}
+		return fbutils.GetInt32Slot(table, slot)
+	case PropertyTypeLong, PropertyTypeDate, PropertyTypeDateNano:
+		if unsigned || property.Flags&PropertyFlagId != 0 {
+			return fbutils.GetUint64Slot(table, slot)
+		}
+		return fbutils.GetInt64Slot(table, slot)
+	case PropertyTypeRelation:
+		return fbutils.GetUint64Slot(table, slot)
+	case PropertyTypeFloat:
+		return fbutils.GetFloat32Slot(table, slot)
+	case PropertyTypeDouble:
+		return fbutils.GetFloat64Slot(table, slot)
+	case PropertyTypeString:
+		return fbutils.GetStringSlot(table, slot)
+	case PropertyTypeByteVector:
+		return fbutils.GetByteVectorSlot(table, slot)
+	case PropertyTypeStringVector:
+		return fbutils.GetStringVectorSlot(table, slot)
+	default:
+		return nil
+	}
+}
+
+// supportsJSON returns whether values of this property can be exported & imported as JSON, see readFlatBuffers()
+func (property *PropertyInfo) supportsJSON() bool {
+	switch property.Type {
+	case PropertyTypeBool, PropertyTypeByte, PropertyTypeChar, PropertyTypeShort, PropertyTypeInt, PropertyTypeLong,
+		PropertyTypeDate, PropertyTypeDateNano, PropertyTypeRelation, PropertyTypeFloat, PropertyTypeDouble,
+		PropertyTypeString, PropertyTypeByteVector, PropertyTypeStringVector:
+		return true
+	default:
+		return false
+	}
+}
+
+// JSON representation of floating-point values not supported by encoding/json
+const (
+	jsonNaN              = "NaN"
+	jsonPositiveInfinity = "Infinity"
+	jsonNegativeInfinity = "-Infinity"
+)
+
+// marshalJSONValue is like json.Marshal() but writes NaN and infinite floating-point values as strings
+func marshalJSONValue(value interface{}) ([]byte, error) {
+	var float float64
+	switch v := value.(type) {
+	case float32:
+		float = float64(v)
+	case float64:
+		float = v
+	default:
+		return json.Marshal(value)
+	}
+
+	switch {
+	case math.IsNaN(float):
+		return json.Marshal(jsonNaN)
+	case math.IsInf(float, 1):
+		return json.Marshal(jsonPositiveInfinity)
+	case math.IsInf(float, -1):
+		return json.Marshal(jsonNegativeInfinity)
+	default:
+		return json.Marshal(value)
+	}
+}
+
+// unmarshalJSONFloat decodes a floating-point value written by marshalJSONValue()
+func unmarshalJSONFloat(raw json.RawMessage, bitSize int) (float64, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		switch text {
+		case jsonNaN:
+			return math.NaN(), nil
+		case jsonPositiveInfinity:
+			return math.Inf(1), nil
+		case jsonNegativeInfinity:
+			return math.Inf(-1), nil
+		default:
+			return 0, fmt.Errorf("invalid floating-point value %q", text)
+		}
+	}
+
+	if bitSize == 32 {
+		var value float32
+		err := json.Unmarshal(raw, &value)
+		return float64(value), err
+	}
+	var value float64
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+// parseJSON decodes the value of this property into a Go type matching readFlatBuffers(); returns nil for JSON null
+func (property *PropertyInfo) parseJSON(raw json.RawMessage) (interface{}, error) {
+	if string(raw) == "null" {
+		return nil, nil
+	}
+
+	var unsigned = property.Flags&PropertyFlagUnsigned != 0
+	var err error
+	switch property.Type {
+	case PropertyTypeBool:
+		var value bool
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeByte, PropertyTypeChar:
+		if unsigned {
+			var value uint8
+			err = json.Unmarshal(raw, &value)
+			return value, err
+		}
+		var value int8
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeShort:
+		if unsigned {
+			var value uint16
+			err = json.Unmarshal(raw, &value)
+			return value, err
+		}
+		var value int16
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeInt:
+		if unsigned {
+			var value uint32
+			err = json.Unmarshal(raw, &value)
+			return value, err
+		}
+		var value int32
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeLong, PropertyTypeDate, PropertyTypeDateNano, PropertyTypeRelation:
+		if unsigned || property.Flags&PropertyFlagId != 0 || property.Type == PropertyTypeRelation {
+			var value uint64
+			err = json.Unmarshal(raw, &value)
+			return value, err
+		}
+		var value int64
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeFloat:
+		value, err := unmarshalJSONFloat(raw, 32)
+		return float32(value), err
+	case PropertyTypeDouble:
+		return unmarshalJSONFloat(raw, 64)
+	case PropertyTypeString:
+		var value string
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeByteVector:
+		var value []byte
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case PropertyTypeStringVector:
+		var value []string
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	default:
+		return nil, fmt.Errorf("unsupported property type %d", property.Type)
+	}
+}
+
+// flattenValues builds a FlatBuffers object from values as returned by parseJSON()
+func flattenValues(fbb *flatbuffers.Builder, values map[*PropertyInfo]interface{}, lastPropertyId TypeId) error {
+	if err := startObjectWithValues(fbb, values, lastPropertyId); err != nil {
+		return err
+	}
+	fbb.Finish(fbb.EndObject())
+	return nil
+}
+
+// startObjectWithValues starts a FlatBuffers object and sets the given values; the caller must end the object
+func startObjectWithValues(fbb *flatbuffers.Builder, values map[*PropertyInfo]interface{}, lastPropertyId TypeId) error {
+	// offsets of non-scalar values must be created before the object is started
+	var offsets = make(map[*PropertyInfo]flatbuffers.UOffsetT)
+	for property, value := range values {
+		switch v := value.(type) {
+		case string:
+			offsets[property] = fbutils.CreateStringOffset(fbb, v)
+		case []byte:
+			offsets[property] = fbutils.CreateByteVectorOffset(fbb, v)
+		case []string:
+			offsets[property] = fbutils.CreateStringVectorOffset(fbb, v)
+		}
+	}
+
+	fbb.StartObject(int(lastPropertyId))
+	for property, value := range values {
+		var slot = int(property.Id - 1)
+		switch v := value.(type) {
+		case bool:
+			fbutils.SetBoolSlot(fbb, slot, v)
+		case int8:
+			fbutils.SetInt8Slot(fbb, slot, v)
+		case uint8:
+			fbutils.SetUint8Slot(fbb, slot, v)
+		case int16:
+			fbutils.SetInt16Slot(fbb, slot, v)
+		case uint16:
+			fbutils.SetUint16Slot(fbb, slot, v)
+		case int32:
+			fbutils.SetInt32Slot(fbb, slot, v)
+		case uint32:
+			fbutils.SetUint32Slot(fbb, slot, v)
+		case int64:
+			fbutils.SetInt64Slot(fbb, slot, v)
+		case uint64:
+			fbutils.SetUint64Slot(fbb, slot, v)
+		case float32:
+			fbutils.SetFloat32Slot(fbb, slot, v)
+		case float64:
+			fbutils.SetFloat64Slot(fbb, slot, v)
+		case string, []byte, []string:
+			if offsets[property] != 0 {
+				fbutils.SetUOffsetTSlot(fbb, slot, offsets[property])
+			}
+		default:
+			return fmt.Errorf("unsupported value type %T", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LogLevel is the severity of a message passed to a Logger. Currently, all the messages are debug messages, enabled by
+// ObjectBox.SetDebugFlags(); the type leaves room for other levels without changing the Logger signature.
+type LogLevel int
+
+const (
+	// LogLevelDebug is used for the messages enabled by SetDebugFlags()
+	LogLevelDebug LogLevel = iota
+)
+
+// String returns a lower-case name of the level, e.g. "debug"
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelDebug:
+		return "debug"
+	}
+	return "unknown"
+}
+
+// Logger receives log messages, see Builder.Logger(). Fields hold structured details of the logged event, e.g.
+// "entity", "duration" (time.Duration) or "error". The function may be called concurrently from multiple goroutines.
+type Logger func(level LogLevel, msg string, fields map[string]interface{})
+
+// logEnabled checks whether a Logger is configured and any of the given debug flags is enabled
+func (ob *ObjectBox) logEnabled(flags uint) bool {
+	return ob.options.logger != nil && atomic.LoadUint32(&ob.debugFlags)&uint32(flags) != 0
+}
+
+// logTx logs a finished transaction if enabled by the debug flags; committed is only relevant for write transactions
+func (ob *ObjectBox) logTx(readOnly bool, committed bool, started time.Time, err error) {
+	var flag uint = DebugflagsLogTransactionsWrite
+	var msg = "write transaction"
+	if readOnly {
+		flag = DebugflagsLogTransactionsRead
+		msg = "read transaction"
+	}
+
+	if !ob.logEnabled(flag) {
+		return
+	}
+
+	if readOnly {
+		msg += " finished"
+	} else if committed {
+		msg += " committed"
+	} else {
+		msg += " aborted"
+	}
+
+	var fields = map[string]interface{}{"duration": time.Since(started)}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	ob.options.logger(LogLevelDebug, msg, fields)
+}
+
+// logExecution returns a function to be deferred by a query operation, logging it if enabled by the debug flags:
+// 		defer query.logExecution("find")(&err)
+func (query *Query) logExecution(operation string) func(err *error) {
+	var ob = query.objectBox
+	if !ob.logEnabled(DebugflagsLogQueries | DebugflagsLogQueryParameters) {
+		return func(*error) {}
+	}
+
+	var started = time.Now()
+	return func(err *error) {
+		var fields = map[string]interface{}{
+			"entity":    query.entity.name,
+			"operation": operation,
+			"duration":  time.Since(started),
+		}
+		if ob.logEnabled(DebugflagsLogQueryParameters) {
+			if params, descErr := query.DescribeParams(); descErr == nil {
+				fields["params"] = params
+			}
+		}
+		if *err != nil {
+			fields["error"] = (*err).Error()
+		}
+		ob.options.logger(LogLevelDebug, "query executed", fields)
+	}
+}
+
+// operation names of async put modes, used in the log
+var asyncPutOperations = map[int]string{
+	cPutModePut:    "put",
+	cPutModeInsert: "insert",
+	cPutModeUpdate: "update",
+}
+
+// logSubmission logs an async queue submission if enabled by the debug flags
+func (async *AsyncBox) logSubmission(operation string, id uint64, err error) {
+	var ob = async.box.ObjectBox
+	if !ob.logEnabled(DebugflagsLogAsyncQueue) {
+		return
+	}
+
+	var fields = map[string]interface{}{
+		"entity":    async.box.entity.name,
+		"operation": operation,
+		"id":        id,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	ob.options.logger(LogLevelDebug, "async operation submitted", fields)
+}
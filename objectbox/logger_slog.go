@@ -0,0 +1,53 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// SlogLogger returns a Logger forwarding all messages to the given slog.Logger, with fields as attributes:
+// 		objectbox.NewBuilder().Logger(objectbox.SlogLogger(slog.Default()))
+func SlogLogger(logger *slog.Logger) Logger {
+	return func(level LogLevel, msg string, fields map[string]interface{}) {
+		var keys = make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var attrs = make([]slog.Attr, len(keys))
+		for i, key := range keys {
+			attrs[i] = slog.Any(key, fields[key])
+		}
+
+		logger.LogAttrs(context.Background(), level.slogLevel(), msg, attrs...)
+	}
+}
+
+func (level LogLevel) slogLevel() slog.Level {
+	switch level {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	}
+	return slog.LevelError
+}
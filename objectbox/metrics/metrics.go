@@ -0,0 +1,101 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics exposes ObjectBox store statistics (see ObjectBox.Stats()) in the Prometheus text exposition format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+)
+
+// Handler returns an HTTP handler serving the current statistics of the given store, to be scraped by Prometheus:
+// 		http.Handle("/metrics", metrics.Handler(ob))
+func Handler(ob *objectbox.ObjectBox) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stats, err := ob.Stats()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = Write(w, stats)
+	})
+}
+
+// Write writes the given statistics in the Prometheus text exposition format
+func Write(w io.Writer, stats *objectbox.Stats) error {
+	var out = bufio.NewWriter(w)
+
+	writeMetric(out, "objectbox_db_file_size_bytes", "gauge", "Size of the database file.",
+		sample{value: stats.FileSize})
+	writeMetric(out, "objectbox_db_max_size_bytes", "gauge", "Maximum size of the database file.",
+		sample{value: stats.MaxSize})
+
+	var entities = make([]string, 0, len(stats.EntityCounts))
+	for name := range stats.EntityCounts {
+		entities = append(entities, name)
+	}
+	sort.Strings(entities)
+	var counts = make([]sample, len(entities))
+	for i, name := range entities {
+		counts[i] = sample{labels: `entity="` + escapeLabel(name) + `"`, value: stats.EntityCounts[name]}
+	}
+	writeMetric(out, "objectbox_entity_objects", "gauge", "Number of stored objects.", counts...)
+
+	// implicit transactions of single Box/Query calls aren't counted, see objectbox.Stats
+	writeMetric(out, "objectbox_explicit_transactions_active", "gauge", "Number of currently open explicit transactions.",
+		sample{labels: `type="read"`, value: stats.ActiveExplicitReadTransactions},
+		sample{labels: `type="write"`, value: stats.ActiveExplicitWriteTransactions})
+	writeMetric(out, "objectbox_explicit_transactions_total", "counter", "Number of finished explicit transactions.",
+		sample{labels: `type="read",result="finished"`, value: stats.ExplicitReadTransactions},
+		sample{labels: `type="write",result="committed"`, value: stats.ExplicitWriteTransactionsCommitted},
+		sample{labels: `type="write",result="aborted"`, value: stats.ExplicitWriteTransactionsAborted})
+	writeMetric(out, "objectbox_async_operations_total", "counter", "Number of operations submitted to the async queue.",
+		sample{labels: `result="submitted"`, value: stats.AsyncSubmitted},
+		sample{labels: `result="rejected"`, value: stats.AsyncRejected})
+
+	return out.Flush()
+}
+
+type sample struct {
+	labels string
+	value  interface{}
+}
+
+func writeMetric(out *bufio.Writer, name, metricType, help string, samples ...sample) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	for _, s := range samples {
+		if len(s.labels) > 0 {
+			fmt.Fprintf(out, "%s{%s} %v\n", name, s.labels, s.value)
+		} else {
+			fmt.Fprintf(out, "%s %v\n", name, s.value)
+		}
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
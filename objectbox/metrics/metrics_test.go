@@ -0,0 +1,70 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+)
+
+func TestWrite(t *testing.T) {
+	var stats = &objectbox.Stats{
+		FileSize:                           8192,
+		MaxSize:                            1024 * 1024 * 1024,
+		EntityCounts:                       map[string]uint64{"Task": 3, "Event": 1},
+		ActiveExplicitReadTransactions:     1,
+		ExplicitReadTransactions:           10,
+		ExplicitWriteTransactionsCommitted: 5,
+		ExplicitWriteTransactionsAborted:   2,
+		AsyncSubmitted:                     7,
+	}
+
+	var buffer bytes.Buffer
+	assert.NoErr(t, Write(&buffer, stats))
+
+	var lines = strings.Split(buffer.String(), "\n")
+	var contains = func(line string) {
+		for _, l := range lines {
+			if l == line {
+				return
+			}
+		}
+		t.Errorf("line %q not found in:\n%s", line, buffer.String())
+	}
+
+	contains("# TYPE objectbox_db_file_size_bytes gauge")
+	contains("objectbox_db_file_size_bytes 8192")
+	contains("objectbox_db_max_size_bytes 1073741824")
+	contains(`objectbox_entity_objects{entity="Event"} 1`)
+	contains(`objectbox_entity_objects{entity="Task"} 3`)
+	contains(`objectbox_explicit_transactions_active{type="read"} 1`)
+	contains(`objectbox_explicit_transactions_active{type="write"} 0`)
+	contains("# TYPE objectbox_explicit_transactions_total counter")
+	contains(`objectbox_explicit_transactions_total{type="read",result="finished"} 10`)
+	contains(`objectbox_explicit_transactions_total{type="write",result="committed"} 5`)
+	contains(`objectbox_explicit_transactions_total{type="write",result="aborted"} 2`)
+	contains(`objectbox_async_operations_total{result="submitted"} 7`)
+	contains(`objectbox_async_operations_total{result="rejected"} 0`)
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Eq(t, `a\"b\\c\nd`, escapeLabel("a\"b\\c\nd"))
+}
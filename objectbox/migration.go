@@ -0,0 +1,222 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+)
+
+// The schema version is stored in an internal entity with a single object, see Builder.Migration(). The entity is only
+// declared in the native model, i.e. it isn't visible through Entities(), boxes, backups or JSON. Its ID is far above
+// the IDs assigned by objectbox-generator so that it doesn't collide with the entities of the application.
+const (
+	schemaEntityName        = "ObjectBoxGoSchema"
+	schemaEntityId   TypeId = 0x7fff0000
+	schemaEntityUid         = 6510838734529390853
+
+	schemaPropertyIdUid      = 2902436575326612373
+	schemaPropertyVersionUid = 8387425417931207519
+
+	// the ID of the only object
+	schemaObjectId = 1
+)
+
+type migration struct {
+	fromVersion uint
+	toVersion   uint
+	fn          func(tx *Tx) error
+}
+
+// Migration registers a data migration from the given schema version to a newer one. When the database is opened,
+// all migrations necessary to get from the stored schema version to the latest one (the highest toVersion of all
+// registered migrations) are executed, each one in a separate write transaction. For example:
+// 		objectbox.NewBuilder().Model(ObjectBoxModel()).
+// 			Migration(0, 1, func(tx *objectbox.Tx) error {
+// 				box, err := tx.Box(TaskBinding.Id)
+// 				...
+// 			}).
+// 			Migration(1, 2, migrateTasksV2).
+// 			Build()
+//
+// The schema version is stored in the database itself, in the same write transaction as the migration, i.e. each
+// migration either runs completely, including the version update, or it has no effect at all.
+//
+// A database without a stored schema version (i.e. created before migrations were introduced) is considered to be
+// version 0. A newly created database starts at the latest version without running any migrations. A database restored
+// from a backup (see RestoreFromFile()) gets the schema version recorded in the backup and runs the migrations from
+// there. Opening a database with a schema version newer than the latest one fails.
+func (builder *Builder) Migration(fromVersion, toVersion uint, fn func(tx *Tx) error) *Builder {
+	if builder.Error != nil {
+		return builder
+	}
+
+	if toVersion <= fromVersion {
+		builder.Error = fmt.Errorf("invalid migration from version %d to %d, the target version must be higher",
+			fromVersion, toVersion)
+		return builder
+	}
+
+	for _, existing := range builder.migrations {
+		if existing.fromVersion == fromVersion {
+			builder.Error = fmt.Errorf("duplicate migration from version %d", fromVersion)
+			return builder
+		}
+	}
+
+	builder.migrations = append(builder.migrations, migration{fromVersion, toVersion, fn})
+	return builder
+}
+
+// latestSchemaVersion returns the highest target version of the registered migrations
+func (builder *Builder) latestSchemaVersion() uint {
+	var latest uint
+	for _, m := range builder.migrations {
+		if m.toVersion > latest {
+			latest = m.toVersion
+		}
+	}
+	return latest
+}
+
+// migrate brings the database to the latest schema version; newDatabase indicates the database was just created
+func (ob *ObjectBox) migrate(builder *Builder, newDatabase bool) error {
+	var latest = builder.latestSchemaVersion()
+
+	if newDatabase {
+		if builder.readOnly {
+			return nil
+		}
+		return ob.storeSchemaVersion(latest)
+	}
+
+	version, err := ob.loadSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > latest {
+		return fmt.Errorf("database schema version %d is newer than the latest known version %d", version, latest)
+	}
+
+	for version < latest {
+		var next *migration
+		for i := range builder.migrations {
+			if builder.migrations[i].fromVersion == version {
+				next = &builder.migrations[i]
+				break
+			}
+		}
+
+		if next == nil {
+			return fmt.Errorf("no migration registered from schema version %d", version)
+		}
+
+		if builder.readOnly {
+			return fmt.Errorf("can't migrate a read-only database from schema version %d", version)
+		}
+
+		if err := ob.runMigration(next); err != nil {
+			return fmt.Errorf("migration from schema version %d to %d failed: %v", next.fromVersion, next.toVersion, err)
+		}
+		version = next.toVersion
+	}
+
+	return nil
+}
+
+func (ob *ObjectBox) runMigration(m *migration) error {
+	tx, err := ob.BeginWrite()
+	if err != nil {
+		return err
+	}
+
+	// the new version is written in the same transaction, i.e. it's only stored if the migration succeeds
+	if err = m.fn(tx); err == nil {
+		err = ob.storeSchemaVersion(m.toVersion)
+	}
+	if err != nil {
+		if abortErr := tx.Abort(); abortErr != nil {
+			return fmt.Errorf("%v; %v", err, abortErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addSchemaEntity declares the internal entity storing the schema version in the native model
+func (model *Model) addSchemaEntity() error {
+	model.Entity(schemaEntityName, schemaEntityId, schemaEntityUid)
+	model.Property("Id", int(PropertyTypeLong), 1, schemaPropertyIdUid)
+	model.PropertyFlags(int(PropertyFlagId))
+	model.Property("Version", int(PropertyTypeLong), 2, schemaPropertyVersionUid)
+	model.PropertyFlags(int(PropertyFlagUnsigned))
+	model.EntityLastPropertyId(2, schemaPropertyVersionUid)
+	model.currentEntity = nil
+
+	if model.lastEntityId < schemaEntityId {
+		model.LastEntityId(schemaEntityId, schemaEntityUid)
+	}
+	return model.Error
+}
+
+// loadSchemaVersion returns the stored schema version; 0 if there's none
+func (ob *ObjectBox) loadSchemaVersion() (version uint, err error) {
+	err = ob.RunInReadTx(func() error {
+		var data unsafe.Pointer
+		var size C.size_t
+		var rc = C.obx_box_get(ob.schemaBox, schemaObjectId, &data, &size)
+		if rc == C.OBX_NOT_FOUND {
+			return nil
+		} else if rc != 0 {
+			return createError()
+		}
+
+		var bytes []byte
+		cVoidPtrToByteSlice(data, int(size), &bytes)
+		var table = &flatbuffers.Table{
+			Bytes: bytes,
+			Pos:   flatbuffers.GetUOffsetT(bytes),
+		}
+		version = uint(fbutils.GetUint64Slot(table, 6))
+		return nil
+	})
+	return version, err
+}
+
+// storeSchemaVersion writes the schema version, as a part of the current write transaction if there's one
+func (ob *ObjectBox) storeSchemaVersion(version uint) error {
+	var fbb = flatbuffers.NewBuilder(32)
+	fbb.StartObject(2)
+	fbutils.SetUint64Slot(fbb, 0, schemaObjectId)
+	fbutils.SetUint64Slot(fbb, 1, uint64(version))
+	fbb.Finish(fbb.EndObject())
+
+	var bytes = fbb.FinishedBytes()
+	return cCall(func() C.obx_err {
+		return C.obx_box_put5(ob.schemaBox, schemaObjectId, unsafe.Pointer(&bytes[0]), C.size_t(len(bytes)), C.OBXPutMode_PUT)
+	})
+}
@@ -23,6 +23,7 @@ package objectbox
 import "C"
 
 import (
+	"errors"
 	"fmt"
 	"unsafe"
 
@@ -78,6 +79,9 @@ type Model struct {
 	lastRelationId  TypeId
 	lastRelationUid uint64
 
+	// sets the remove policy of the most recently declared relation, see RelationRemovePolicy()
+	lastRelationPolicy func(policy RemovePolicy)
+
 	generatorVersion int
 }
 
@@ -157,6 +161,7 @@ func (model *Model) Entity(name string, id TypeId, uid uint64) {
 		name: name,
 		id:   id,
 	}
+	model.lastRelationPolicy = nil
 }
 
 // EntityFlags configures behavior of entities
@@ -183,6 +188,15 @@ func (model *Model) Relation(relationId TypeId, relationUid uint64, targetEntity
 	})
 
 	model.currentEntity.hasRelations = true
+	model.currentEntity.relations = append(model.currentEntity.relations, RelationInfo{
+		Id:             relationId,
+		TargetEntityId: targetEntityId,
+	})
+
+	var entity, index = model.currentEntity, len(model.currentEntity.relations) - 1
+	model.lastRelationPolicy = func(policy RemovePolicy) {
+		entity.relations[index].RemovePolicy = policy
+	}
 }
 
 // EntityLastPropertyId declares a property with the highest ID.
@@ -208,6 +222,14 @@ func (model *Model) Property(name string, propertyType int, id TypeId, uid uint6
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_property(model.cModel, cname, C.OBXPropertyType(propertyType), C.obx_schema_id(id), C.obx_uid(uid))
 	})
+
+	if model.Error == nil {
+		model.currentEntity.properties = append(model.currentEntity.properties, &PropertyInfo{
+			Id:   id,
+			Name: name,
+			Type: PropertyType(propertyType),
+		})
+	}
 }
 
 // PropertyFlags configures type and other information about the property
@@ -218,6 +240,10 @@ func (model *Model) PropertyFlags(propertyFlags int) {
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_property_flags(model.cModel, C.OBXPropertyFlags(propertyFlags))
 	})
+
+	if property := model.currentEntity.lastProperty(); model.Error == nil && property != nil {
+		property.Flags = PropertyFlags(propertyFlags)
+	}
 }
 
 // PropertyIndex creates a new index on the property
@@ -228,6 +254,10 @@ func (model *Model) PropertyIndex(id TypeId, uid uint64) {
 	model.Error = cCall(func() C.obx_err {
 		return C.obx_model_property_index_id(model.cModel, C.obx_schema_id(id), C.obx_uid(uid))
 	})
+
+	if property := model.currentEntity.lastProperty(); model.Error == nil && property != nil {
+		property.IndexId = id
+	}
 }
 
 // PropertyRelation adds a property-based (i.e. to-one) relation
@@ -243,9 +273,33 @@ func (model *Model) PropertyRelation(targetEntityName string, indexId TypeId, in
 		return C.obx_model_property_relation(model.cModel, cname, C.obx_schema_id(indexId), C.obx_uid(indexUid))
 	})
 
+	if property := model.currentEntity.lastProperty(); model.Error == nil && property != nil {
+		property.RelationTarget = targetEntityName
+		property.IndexId = indexId
+		model.lastRelationPolicy = func(policy RemovePolicy) {
+			property.RemovePolicy = policy
+		}
+	}
+
 	model.currentEntity.hasRelations = true
 }
 
+// RelationRemovePolicy configures the remove policy of the relation declared just before, i.e. using
+// PropertyRelation() or Relation(). The policy is applied when a target object of the relation is removed.
+func (model *Model) RelationRemovePolicy(policy RemovePolicy) {
+	if model.Error != nil {
+		return
+	}
+
+	if model.lastRelationPolicy == nil {
+		model.Error = errors.New("invalid binding - RelationRemovePolicy() must follow PropertyRelation() or Relation()")
+		return
+	}
+
+	model.lastRelationPolicy(policy)
+	model.lastRelationPolicy = nil
+}
+
 // RegisterBinding attaches generated binding code to the model.
 // The binding is used by ObjectBox for marshalling and other typed operations.
 func (model *Model) RegisterBinding(binding ObjectBinding) {
@@ -0,0 +1,218 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PropertyType is the type of a property as stored in the database
+type PropertyType int
+
+// Property types, see PropertyInfo.Type
+const (
+	PropertyTypeBool         PropertyType = C.OBXPropertyType_Bool
+	PropertyTypeByte         PropertyType = C.OBXPropertyType_Byte
+	PropertyTypeShort        PropertyType = C.OBXPropertyType_Short
+	PropertyTypeChar         PropertyType = C.OBXPropertyType_Char
+	PropertyTypeInt          PropertyType = C.OBXPropertyType_Int
+	PropertyTypeLong         PropertyType = C.OBXPropertyType_Long
+	PropertyTypeFloat        PropertyType = C.OBXPropertyType_Float
+	PropertyTypeDouble       PropertyType = C.OBXPropertyType_Double
+	PropertyTypeString       PropertyType = C.OBXPropertyType_String
+	PropertyTypeDate         PropertyType = C.OBXPropertyType_Date
+	PropertyTypeRelation     PropertyType = C.OBXPropertyType_Relation
+	PropertyTypeDateNano     PropertyType = C.OBXPropertyType_DateNano
+	PropertyTypeByteVector   PropertyType = C.OBXPropertyType_ByteVector
+	PropertyTypeStringVector PropertyType = C.OBXPropertyType_StringVector
+)
+
+var propertyTypeNames = map[PropertyType]string{
+	PropertyTypeBool:         "Bool",
+	PropertyTypeByte:         "Byte",
+	PropertyTypeShort:        "Short",
+	PropertyTypeChar:         "Char",
+	PropertyTypeInt:          "Int",
+	PropertyTypeLong:         "Long",
+	PropertyTypeFloat:        "Float",
+	PropertyTypeDouble:       "Double",
+	PropertyTypeString:       "String",
+	PropertyTypeDate:         "Date",
+	PropertyTypeRelation:     "Relation",
+	PropertyTypeDateNano:     "DateNano",
+	PropertyTypeByteVector:   "ByteVector",
+	PropertyTypeStringVector: "StringVector",
+}
+
+// String returns the name of the type, e.g. "Long"
+func (propertyType PropertyType) String() string {
+	if name, ok := propertyTypeNames[propertyType]; ok {
+		return name
+	}
+	return fmt.Sprintf("PropertyType(%d)", int(propertyType))
+}
+
+// PropertyFlags is a bit combination of property flags
+type PropertyFlags int
+
+// Property flags, see PropertyInfo.Flags
+const (
+	PropertyFlagId                   PropertyFlags = C.OBXPropertyFlags_ID
+	PropertyFlagNonPrimitiveType     PropertyFlags = C.OBXPropertyFlags_NON_PRIMITIVE_TYPE
+	PropertyFlagNotNull              PropertyFlags = C.OBXPropertyFlags_NOT_NULL
+	PropertyFlagIndexed              PropertyFlags = C.OBXPropertyFlags_INDEXED
+	PropertyFlagUnique               PropertyFlags = C.OBXPropertyFlags_UNIQUE
+	PropertyFlagIdMonotonicSequence  PropertyFlags = C.OBXPropertyFlags_ID_MONOTONIC_SEQUENCE
+	PropertyFlagIdSelfAssignable     PropertyFlags = C.OBXPropertyFlags_ID_SELF_ASSIGNABLE
+	PropertyFlagIndexPartialSkipNull PropertyFlags = C.OBXPropertyFlags_INDEX_PARTIAL_SKIP_NULL
+	PropertyFlagIndexPartialSkipZero PropertyFlags = C.OBXPropertyFlags_INDEX_PARTIAL_SKIP_ZERO
+	PropertyFlagVirtual              PropertyFlags = C.OBXPropertyFlags_VIRTUAL
+	PropertyFlagIndexHash            PropertyFlags = C.OBXPropertyFlags_INDEX_HASH
+	PropertyFlagIndexHash64          PropertyFlags = C.OBXPropertyFlags_INDEX_HASH64
+	PropertyFlagUnsigned             PropertyFlags = C.OBXPropertyFlags_UNSIGNED
+	PropertyFlagIdCompanion          PropertyFlags = C.OBXPropertyFlags_ID_COMPANION
+)
+
+var propertyFlagNames = []struct {
+	flag PropertyFlags
+	name string
+}{
+	{PropertyFlagId, "Id"},
+	{PropertyFlagNonPrimitiveType, "NonPrimitiveType"},
+	{PropertyFlagNotNull, "NotNull"},
+	{PropertyFlagIndexed, "Indexed"},
+	{PropertyFlagUnique, "Unique"},
+	{PropertyFlagIdMonotonicSequence, "IdMonotonicSequence"},
+	{PropertyFlagIdSelfAssignable, "IdSelfAssignable"},
+	{PropertyFlagIndexPartialSkipNull, "IndexPartialSkipNull"},
+	{PropertyFlagIndexPartialSkipZero, "IndexPartialSkipZero"},
+	{PropertyFlagVirtual, "Virtual"},
+	{PropertyFlagIndexHash, "IndexHash"},
+	{PropertyFlagIndexHash64, "IndexHash64"},
+	{PropertyFlagUnsigned, "Unsigned"},
+	{PropertyFlagIdCompanion, "IdCompanion"},
+}
+
+// String returns the names of the flags separated by "|", e.g. "Indexed|Unsigned"
+func (flags PropertyFlags) String() string {
+	var names []string
+	for _, f := range propertyFlagNames {
+		if flags&f.flag != 0 {
+			names = append(names, f.name)
+			flags &^= f.flag
+		}
+	}
+	if flags != 0 {
+		names = append(names, fmt.Sprintf("PropertyFlags(%d)", int(flags)))
+	}
+	return strings.Join(names, "|")
+}
+
+// PropertyInfo describes a property of an entity, see EntityInfo.Properties()
+type PropertyInfo struct {
+	Id    TypeId
+	Name  string
+	Type  PropertyType
+	Flags PropertyFlags
+
+	// IndexId is the ID of the index on this property or 0 if the property isn't indexed
+	IndexId TypeId
+
+	// RelationTarget is the target entity name of a to-one relation property (Type is PropertyTypeRelation)
+	RelationTarget string
+
+	// RemovePolicy of a to-one relation property, applied when the target object is removed
+	RemovePolicy RemovePolicy
+}
+
+// RelationInfo describes a standalone (many-to-many) relation, see EntityInfo.Relations()
+type RelationInfo struct {
+	Id             TypeId
+	TargetEntityId TypeId
+
+	// RemovePolicy applied when a target object is removed
+	RemovePolicy RemovePolicy
+}
+
+// EntityInfo describes an entity registered in the model, see ObjectBox.Entities()
+type EntityInfo struct {
+	entity *entity
+}
+
+// Id returns the entity ID
+func (info *EntityInfo) Id() TypeId {
+	return info.entity.id
+}
+
+// Name returns the entity name
+func (info *EntityInfo) Name() string {
+	return info.entity.name
+}
+
+// Properties returns the properties in the order they are defined in the model
+func (info *EntityInfo) Properties() []PropertyInfo {
+	var result = make([]PropertyInfo, len(info.entity.properties))
+	for i, property := range info.entity.properties {
+		result[i] = *property
+	}
+	return result
+}
+
+// Property returns the property with the given name or nil if there's no such property
+func (info *EntityInfo) Property(name string) *PropertyInfo {
+	for _, property := range info.entity.properties {
+		if property.Name == name {
+			var result = *property
+			return &result
+		}
+	}
+	return nil
+}
+
+// Relations returns the standalone (many-to-many) relations with this entity as the source
+func (info *EntityInfo) Relations() []RelationInfo {
+	var result = make([]RelationInfo, len(info.entity.relations))
+	copy(result, info.entity.relations)
+	return result
+}
+
+// Entities returns all entities in the model, ordered by ID
+func (ob *ObjectBox) Entities() []*EntityInfo {
+	var result = make([]*EntityInfo, 0, len(ob.entitiesById))
+	for _, entity := range ob.entitiesById {
+		result = append(result, &EntityInfo{entity})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].entity.id < result[j].entity.id
+	})
+	return result
+}
+
+// Entity returns the entity with the given name or nil if there's no such entity
+func (ob *ObjectBox) Entity(name string) *EntityInfo {
+	if entity := ob.entitiesByName[name]; entity != nil {
+		return &EntityInfo{entity}
+	}
+	return nil
+}
@@ -24,11 +24,15 @@ package objectbox
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"runtime"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -78,12 +82,28 @@ type ObjectBox struct {
 	entitiesByName map[string]*entity
 	boxes          map[TypeId]*Box
 	boxesMutex     sync.Mutex
+	schemaBox      *C.OBX_box // internal, storing the schema version, see Builder.Migration()
 	options        options
 	syncClient     *SyncClient
+	observers      map[*Observer]bool
+	observersMutex sync.Mutex
+
+	// observer goroutines (callbacks) still running, see closeObservers()
+	observersRunning sync.WaitGroup
+
+	directory              string
+	removeDirectoryOnClose bool
+
+	// debug flags handled by the Go binding if a Logger is configured; accessed atomically
+	debugFlags uint32
+
+	stats       *statsCounters
+	maxSizeInKb uint64
 }
 
 type options struct {
-	asyncTimeout uint
+	asyncTimeout uint // 0 = the core default
+	logger       Logger
 }
 
 // constant during runtime so no need to call this each time it's necessary
@@ -91,16 +111,33 @@ var supportsResultArray = bool(C.obx_has_feature(C.OBXFeature_ResultArray))
 
 // Close fully closes the database and frees resources
 func (ob *ObjectBox) Close() {
+	// callbacks still running may access the database so they need to finish before the store is closed
+	ob.closeObservers()
 	storeToClose := ob.store
 	ob.store = nil
 	if ob.syncClient != nil {
 		_ = ob.syncClient.Close()
 	}
 	if storeToClose != nil {
+		if ob.options.asyncTimeout > 0 {
+			ob.boxesMutex.Lock()
+			for _, box := range ob.boxes {
+				_ = box.async.close()
+			}
+			ob.boxesMutex.Unlock()
+		}
 		C.obx_store_close(storeToClose)
+		if ob.removeDirectoryOnClose {
+			os.RemoveAll(ob.directory)
+		}
 	}
 }
 
+// Directory returns the path of the database directory, see Builder.Directory() and Builder.TempDirectory()
+func (ob *ObjectBox) Directory() string {
+	return ob.directory
+}
+
 // RunInReadTx executes the given function inside a read transaction.
 // The execution of the function `fn` must be sequential and executed in the same thread, which is enforced internally.
 // If you launch goroutines inside `fn`, they will be executed on separate threads and not part of the same transaction.
@@ -120,10 +157,38 @@ func (ob *ObjectBox) RunInWriteTx(fn func() error) error {
 	return ob.runInTxn(false, fn)
 }
 
+// RunInReadTxCtx is like RunInReadTx() but doesn't start the transaction if the given context is already done.
+// The context is checked again after `fn` returns and its error is returned if it was cancelled in the meantime.
+// Use the context inside `fn` to stop long-running reads early.
+func (ob *ObjectBox) RunInReadTxCtx(ctx context.Context, fn func() error) error {
+	return ob.runInTxnCtx(ctx, true, fn)
+}
+
+// RunInWriteTxCtx is like RunInWriteTx() but doesn't start the transaction if the given context is already done.
+// The context is checked again after `fn` returns; if it was cancelled in the meantime, or its deadline exceeded,
+// the transaction is aborted (rolled-back) and the context's error is returned.
+func (ob *ObjectBox) RunInWriteTxCtx(ctx context.Context, fn func() error) error {
+	return ob.runInTxnCtx(ctx, false, fn)
+}
+
+func (ob *ObjectBox) runInTxnCtx(ctx context.Context, readOnly bool, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return ob.runInTxn(readOnly, func() error {
+		if err := fn(); err != nil {
+			return err
+		}
+		return ctx.Err()
+	})
+}
+
 func (ob *ObjectBox) runInTxn(readOnly bool, fn func() error) (err error) {
 	// NOTE if runtime.LockOSThread() is about to be removed, evaluate use of createError() inside transactions
 	runtime.LockOSThread()
 
+	var started = time.Now()
 	var cTxn *C.OBX_txn
 	if readOnly {
 		cTxn = C.obx_txn_read(ob.store)
@@ -137,6 +202,8 @@ func (ob *ObjectBox) runInTxn(readOnly bool, fn func() error) (err error) {
 		return err
 	}
 
+	ob.txStarted(readOnly)
+
 	// Defer to ensure a TX is ALWAYS closed, even in a panic
 	defer func() {
 		if rc := C.obx_txn_close(cTxn); rc != 0 {
@@ -148,6 +215,8 @@ func (ob *ObjectBox) runInTxn(readOnly bool, fn func() error) (err error) {
 		}
 
 		runtime.UnlockOSThread()
+		ob.txFinished(readOnly, err == nil)
+		ob.logTx(readOnly, err == nil, started, err)
 	}()
 
 	err = fn()
@@ -180,11 +249,20 @@ func (ob *ObjectBox) getEntityByName(name string) *entity {
 }
 
 // SetDebugFlags configures debug logging of the ObjectBox core.
-// See DebugFlags* constants
+// See DebugFlags* constants.
+// The flags are always passed to the native library; if a Logger was configured using Builder.Logger(), the enabled
+// events are additionally logged through it.
 func (ob *ObjectBox) SetDebugFlags(flags uint) error {
-	return cCall(func() C.obx_err {
+	if err := cCall(func() C.obx_err {
 		return C.obx_store_debug_flags(ob.store, C.OBXDebugFlags(flags))
-	})
+	}); err != nil {
+		return err
+	}
+
+	if ob.options.logger != nil {
+		atomic.StoreUint32(&ob.debugFlags, uint32(flags))
+	}
+	return nil
 }
 
 // InternalBox returns an Entity Box or panics on error (in case entity with the given ID doesn't exist)
@@ -221,6 +299,33 @@ func (ob *ObjectBox) AwaitAsyncCompletion() error {
 	})
 }
 
+// AwaitAsyncCompletionCtx is like AwaitAsyncCompletion() but returns the context's error as soon as the given context
+// is cancelled or its deadline exceeded. Note: the async queue itself is not affected by the context.
+// Note: if the context is done first, the native wait is left running in a background goroutine until it returns.
+func (ob *ObjectBox) AwaitAsyncCompletionCtx(ctx context.Context) error {
+	return awaitCtx(ctx, ob.AwaitAsyncCompletion)
+}
+
+// awaitCtx runs the given blocking function in a separate goroutine and waits until it finishes or the context is
+// done, whichever comes first. In the latter case, the function keeps running in the background until it returns.
+func awaitCtx(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var result = make(chan error, 1)
+	go func() {
+		result <- fn()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // SyncClient returns an existing client associated with the store or nil if not available.
 // Use NewSyncClient() to create it the first time.
 func (ob *ObjectBox) SyncClient() (*SyncClient, error) {
@@ -0,0 +1,292 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// Observer is a handle to an active data change subscription, as returned by ObjectBox.Subscribe() & Query.Subscribe().
+// Call Unsubscribe() to stop receiving notifications.
+//
+// Notifications are delivered on a separate goroutine (one per Observer) so the callback may freely access the database.
+// Changes committed in a quick succession may be coalesced into a single notification.
+// ObjectBox.Close() waits for callbacks still running, thus it must not be called from inside a callback.
+type Observer struct {
+	objectBox *ObjectBox
+	cObserver *C.OBX_observer
+	cCallback cCallbackId
+
+	// entities to notify about; nil means all entities
+	entityIds map[TypeId]bool
+
+	// the private query used by the callback of Query.Subscribe(), closed when the observer stops
+	query *Query
+
+	mutex   sync.Mutex
+	closed  bool
+	pending map[TypeId]bool
+	signal  chan struct{}
+}
+
+// Subscribe registers a callback which is called after a write transaction changing any of the given entity types
+// was committed. If no entityIds are given, changes to all entity types are observed.
+// The callback receives IDs of the changed entity types (only those matching the subscription), in ascending order.
+func (ob *ObjectBox) Subscribe(fn func(entityIds []TypeId), entityIds ...TypeId) (*Observer, error) {
+	if fn == nil {
+		return nil, errors.New("callback function must not be nil")
+	} else if ob.store == nil {
+		return nil, errors.New("can't subscribe to a closed store")
+	}
+
+	var observer = &Observer{
+		objectBox: ob,
+		pending:   make(map[TypeId]bool),
+		signal:    make(chan struct{}, 1),
+	}
+
+	if len(entityIds) > 0 {
+		observer.entityIds = make(map[TypeId]bool, len(entityIds))
+		for _, id := range entityIds {
+			if ob.entitiesById[id] == nil {
+				return nil, fmt.Errorf("no entity registered for entity ID %d", id)
+			}
+			observer.entityIds[id] = true
+		}
+	}
+
+	var err error
+	observer.cCallback, err = cCallbackRegister(cVoidConstVoidSizeCallback(func(cIds unsafe.Pointer, count uint) {
+		observer.notify(cIdsToTypeIds(cIds, count))
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cCallBool(func() bool {
+		observer.cObserver = C.obx_observe(ob.store, (*C.obx_observer)(cVoidConstVoidSizeCallbackDispatchPtr), observer.cCallback.cPtrArg())
+		return observer.cObserver != nil
+	}); err != nil {
+		cCallbackUnregister(observer.cCallback)
+		return nil, err
+	}
+
+	ob.observersMutex.Lock()
+	if ob.observers == nil {
+		ob.observers = make(map[*Observer]bool)
+	}
+	ob.observers[observer] = true
+	ob.observersMutex.Unlock()
+
+	ob.observersRunning.Add(1)
+	go observer.run(fn)
+
+	return observer, nil
+}
+
+// Subscribe registers a callback which receives the query results (as returned by Find()) whenever objects of the
+// queried entity type, or any of the entity types linked by the query, are changed.
+// The callback is also called once right after subscribing, with the current results.
+//
+// Because the callback runs on another goroutine, the observer executes its own copy of the query, with the
+// parameters, offset, limit and related objects loading as set at the time of subscribing. Later changes to this
+// Query, including closing it, don't affect the subscription.
+func (query *Query) Subscribe(fn func(objects interface{}, err error)) (*Observer, error) {
+	if fn == nil {
+		return nil, errors.New("callback function must not be nil")
+	} else if query.objectBox.store == nil {
+		return nil, errors.New("can't subscribe to a closed store")
+	} else if err := query.check(); err != nil {
+		return nil, err
+	}
+
+	private, err := query.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var entityIds = append([]TypeId{query.entity.id}, query.linkedEntityIds...)
+	observer, err := query.objectBox.Subscribe(func([]TypeId) {
+		fn(private.Find())
+	}, entityIds...)
+	if err != nil {
+		_ = private.Close()
+		return nil, err
+	}
+
+	// the query is closed by run() after the last callback, see Observer.query
+	observer.mutex.Lock()
+	if observer.closed {
+		_ = private.Close()
+	} else {
+		observer.query = private
+	}
+	observer.mutex.Unlock()
+
+	// deliver the initial results
+	observer.notify([]TypeId{query.entity.id})
+	return observer, nil
+}
+
+// clone builds a new query with the same conditions as this one and copies its current settings.
+// The clone uses a regular box, i.e. it isn't bound to a transaction (see Tx.Box()) even if this query is.
+func (query *Query) clone() (*Query, error) {
+	box, err := query.objectBox.box(query.entity.id)
+	if err != nil {
+		return nil, err
+	}
+
+	clone, err := box.QueryOrError(query.conditions...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, setter := range query.params {
+		if err = setter(clone); err != nil {
+			_ = clone.Close()
+			return nil, err
+		}
+	}
+
+	if err = clone.Offset(query.offset).offsetErr; err == nil {
+		err = clone.Limit(query.limit).limitErr
+	}
+	if err != nil {
+		_ = clone.Close()
+		return nil, err
+	}
+
+	clone.eager, clone.noEager = query.eager, query.noEager
+	return clone, nil
+}
+
+// Unsubscribe stops delivering notifications and frees (native) resources held by this Observer.
+// It's safe to call Unsubscribe() multiple times, or from within the Observer's own callback.
+// Unsubscribe() doesn't wait for a callback that is already running; ObjectBox.Close() does.
+func (observer *Observer) Unsubscribe() error {
+	observer.mutex.Lock()
+	if observer.closed {
+		observer.mutex.Unlock()
+		return nil
+	}
+	observer.closed = true
+	close(observer.signal)
+	observer.mutex.Unlock()
+
+	observer.objectBox.observersMutex.Lock()
+	delete(observer.objectBox.observers, observer)
+	observer.objectBox.observersMutex.Unlock()
+
+	var err = cCall(func() C.obx_err {
+		return C.obx_observer_close(observer.cObserver)
+	})
+	cCallbackUnregister(observer.cCallback)
+	return err
+}
+
+// closeObservers unsubscribes all observers still active and waits for their callbacks to finish, called when closing
+// the store
+func (ob *ObjectBox) closeObservers() {
+	ob.observersMutex.Lock()
+	var observers = make([]*Observer, 0, len(ob.observers))
+	for observer := range ob.observers {
+		observers = append(observers, observer)
+	}
+	ob.observersMutex.Unlock()
+
+	for _, observer := range observers {
+		_ = observer.Unsubscribe()
+	}
+
+	ob.observersRunning.Wait()
+}
+
+// notify is called by the native observer (on the committing thread) and must not access the database
+func (observer *Observer) notify(entityIds []TypeId) {
+	observer.mutex.Lock()
+	defer observer.mutex.Unlock()
+
+	if observer.closed {
+		return
+	}
+
+	var matched = false
+	for _, id := range entityIds {
+		if observer.entityIds == nil || observer.entityIds[id] {
+			observer.pending[id] = true
+			matched = true
+		}
+	}
+
+	if matched {
+		// don't block the committing thread; a pending signal already covers this change
+		select {
+		case observer.signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run delivers notifications until the observer is unsubscribed
+func (observer *Observer) run(fn func(entityIds []TypeId)) {
+	defer observer.objectBox.observersRunning.Done()
+	defer func() {
+		observer.mutex.Lock()
+		var query = observer.query
+		observer.query = nil
+		observer.mutex.Unlock()
+		if query != nil {
+			_ = query.Close()
+		}
+	}()
+
+	for range observer.signal {
+		observer.mutex.Lock()
+		var entityIds = make([]TypeId, 0, len(observer.pending))
+		for id := range observer.pending {
+			entityIds = append(entityIds, id)
+		}
+		observer.pending = make(map[TypeId]bool)
+		var closed = observer.closed
+		observer.mutex.Unlock()
+
+		if closed || len(entityIds) == 0 {
+			continue
+		}
+
+		sort.Slice(entityIds, func(i, j int) bool { return entityIds[i] < entityIds[j] })
+		fn(entityIds)
+	}
+}
+
+func cIdsToTypeIds(cIds unsafe.Pointer, count uint) []TypeId {
+	var ids = make([]TypeId, count)
+	for i := uint(0); i < count; i++ {
+		ids[i] = TypeId(*(*C.obx_schema_id)(unsafe.Pointer(uintptr(cIds) + uintptr(i)*unsafe.Sizeof(C.obx_schema_id(0)))))
+	}
+	return ids
+}
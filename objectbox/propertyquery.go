@@ -0,0 +1,647 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// PropertyQuery provides access to values or aggregate functions over a single property of all objects matching
+// the Query it was created from.
+//
+// For example, you can compute the average age of all people whose last name starts with an 'N':
+// 		box.Query(Person_.LastName.HasPrefix("N", false)).Property(Person_.Age).Average()
+//
+// Note: typed shortcuts on the generated *EntityQuery types (e.g. returning []T instead of []interface{}) are not
+// available here because they depend on code generated by objectbox-generator; use Query.Property() meanwhile.
+type PropertyQuery struct {
+	query       *Query
+	property    *PropertyInfo
+	cQuery      *C.OBX_query_prop
+	closeMutex  sync.Mutex
+	distinct    bool
+	distinctErr error
+}
+
+// queryProperty identifies a property in a query; implemented by BaseProperty and all Property* types embedding it
+type queryProperty interface {
+	propertyId() TypeId
+	entityId() TypeId
+}
+
+// Property creates a PropertyQuery over the given property of all objects matching this query.
+// The property must belong to the entity this query was created for.
+// Note: this function panics if you pass a property of an alien entity type.
+// This is typically a programming error. Use PropertyOrError instead if you want the explicit error check.
+func (query *Query) Property(prop queryProperty) *PropertyQuery {
+	pq, err := query.PropertyOrError(prop)
+	if err != nil {
+		panic(fmt.Sprintf("Could not create property query: %s", err))
+	}
+	return pq
+}
+
+// PropertyOrError is like Property() but with error handling.
+func (query *Query) PropertyOrError(prop queryProperty) (*PropertyQuery, error) {
+	defer runtime.KeepAlive(query)
+
+	if err := query.check(); err != nil {
+		return nil, err
+	}
+
+	if prop.entityId() != query.entity.id {
+		return nil, fmt.Errorf("property from a different entity %d passed, expected %d", prop.entityId(), query.entity.id)
+	}
+
+	var pq = &PropertyQuery{
+		query:    query,
+		property: query.entity.property(prop.propertyId()),
+	}
+	if pq.property == nil {
+		return nil, fmt.Errorf("property %d not found on entity %s", prop.propertyId(), query.entity.name)
+	}
+
+	query.closeMutex.Lock()
+	defer query.closeMutex.Unlock()
+
+	if err := cCallBool(func() bool {
+		pq.cQuery = C.obx_query_prop(query.cQuery, C.obx_schema_id(pq.property.Id))
+		return pq.cQuery != nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if query.propertyQueries == nil {
+		query.propertyQueries = make(map[*PropertyQuery]bool)
+	}
+	query.propertyQueries[pq] = true
+
+	pq.installFinalizer()
+	return pq, nil
+}
+
+// Close frees (native) resources held by this PropertyQuery.
+// Note that this is optional and not required because the GC invokes a finalizer automatically.
+// Closing the Query this PropertyQuery was created from closes the PropertyQuery as well.
+func (pq *PropertyQuery) Close() error {
+	// the query's lock is taken first, same as in Query.Close()
+	pq.query.closeMutex.Lock()
+	defer pq.query.closeMutex.Unlock()
+
+	delete(pq.query.propertyQueries, pq)
+	return pq.closeLocked()
+}
+
+// closeLocked closes the native property query; the caller must hold the lock of the query it was created from
+func (pq *PropertyQuery) closeLocked() error {
+	pq.closeMutex.Lock()
+	defer pq.closeMutex.Unlock()
+
+	if pq.cQuery != nil {
+		return cCall(func() C.obx_err {
+			var err = C.obx_query_prop_close(pq.cQuery)
+			pq.cQuery = nil
+			return err
+		})
+	}
+	return nil
+}
+
+func propertyQueryFinalizer(pq *PropertyQuery) {
+	err := pq.Close()
+	if err != nil {
+		fmt.Printf("Error while finalizer closed property query: %s", err)
+	}
+}
+
+// The native property query is tied to the query it was created from; the reference kept in pq.query makes sure the
+// query is not finalized (closed) before this property query. While the query is open, it references this property
+// query too, so an unclosed PropertyQuery is only finalized together with its query.
+func (pq *PropertyQuery) installFinalizer() {
+	runtime.SetFinalizer(pq, propertyQueryFinalizer)
+}
+
+func (pq *PropertyQuery) check() error {
+	if pq.cQuery == nil {
+		return errors.New("illegal state; property query was closed")
+	} else if pq.query.cQuery == nil {
+		return errors.New("illegal state; query was closed")
+	} else if pq.distinctErr != nil {
+		return pq.distinctErr
+	}
+
+	return nil
+}
+
+// Distinct configures the property query to work only on distinct values.
+// Distinct is supported by Count() and the Find*() methods on scalar and string properties; other methods, as well as
+// all methods on vector properties, return an error if distinct is enabled.
+func (pq *PropertyQuery) Distinct(distinct bool) *PropertyQuery {
+	if pq.distinctErr = pq.checkDistinctType(distinct); pq.distinctErr == nil {
+		pq.distinctErr = cCall(func() C.obx_err { return C.obx_query_prop_distinct(pq.cQuery, C.bool(distinct)) })
+	}
+	pq.distinct = distinct
+	runtime.KeepAlive(pq)
+	return pq
+}
+
+// DistinctString configures the property query to work only on distinct values of a string property.
+// The caseSensitive argument defines whether e.g. "abc" and "ABC" are considered distinct.
+// Distinct is supported by Count() and FindStrings(); other methods return an error if distinct is enabled.
+func (pq *PropertyQuery) DistinctString(distinct, caseSensitive bool) *PropertyQuery {
+	if distinct && pq.property.Type != PropertyTypeString {
+		pq.distinctErr = fmt.Errorf("DistinctString() can only be used on a string property, %s has type %d",
+			pq.property.Name, pq.property.Type)
+	} else {
+		pq.distinctErr = cCall(func() C.obx_err {
+			return C.obx_query_prop_distinct_case(pq.cQuery, C.bool(distinct), C.bool(caseSensitive))
+		})
+	}
+	pq.distinct = distinct
+	runtime.KeepAlive(pq)
+	return pq
+}
+
+func (pq *PropertyQuery) checkDistinctType(distinct bool) error {
+	if distinct && (pq.property.Type == PropertyTypeByteVector || pq.property.Type == PropertyTypeStringVector) {
+		return fmt.Errorf("distinct is not supported on property %s of type %d", pq.property.Name, pq.property.Type)
+	}
+	return nil
+}
+
+// checkAggregate is like check() but also fails if distinct is enabled, which aggregate functions don't support
+func (pq *PropertyQuery) checkAggregate(method string) error {
+	if err := pq.check(); err != nil {
+		return err
+	} else if pq.distinct {
+		return fmt.Errorf("%s() doesn't support distinct, only Count() and the Find*() methods do", method)
+	}
+	return nil
+}
+
+// Count returns the number of non-nil values of the property across all objects matching the query.
+// In combination with Distinct(true), only distinct values are counted.
+func (pq *PropertyQuery) Count() (uint64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return 0, err
+	}
+
+	var cResult C.uint64_t
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_count(pq.cQuery, &cResult) }); err != nil {
+		return 0, err
+	}
+	return uint64(cResult), nil
+}
+
+// Average calculates an average value of the numeric property across all objects matching the query.
+func (pq *PropertyQuery) Average() (float64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("Average"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.double
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_avg(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return float64(cResult), nil
+}
+
+// AverageInt calculates an average value of the integer property across all objects matching the query.
+// As opposed to Average(), the computation is done using integer arithmetic.
+func (pq *PropertyQuery) AverageInt() (int64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("AverageInt"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.int64_t
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_avg_int(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return int64(cResult), nil
+}
+
+// Min finds the minimum value of the floating-point property across all objects matching the query.
+func (pq *PropertyQuery) Min() (float64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("Min"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.double
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_min(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return float64(cResult), nil
+}
+
+// Max finds the maximum value of the floating-point property across all objects matching the query.
+func (pq *PropertyQuery) Max() (float64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("Max"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.double
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_max(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return float64(cResult), nil
+}
+
+// Sum calculates the sum of the floating-point property across all objects matching the query.
+func (pq *PropertyQuery) Sum() (float64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("Sum"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.double
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_sum(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return float64(cResult), nil
+}
+
+// MinInt finds the minimum value of the integer property across all objects matching the query.
+func (pq *PropertyQuery) MinInt() (int64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("MinInt"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.int64_t
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_min_int(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return int64(cResult), nil
+}
+
+// MaxInt finds the maximum value of the integer property across all objects matching the query.
+func (pq *PropertyQuery) MaxInt() (int64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("MaxInt"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.int64_t
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_max_int(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return int64(cResult), nil
+}
+
+// SumInt calculates the sum of the integer property across all objects matching the query.
+// Returns an error if the result doesn't fit into an int64.
+func (pq *PropertyQuery) SumInt() (int64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.checkAggregate("SumInt"); err != nil {
+		return 0, err
+	}
+
+	var cResult C.int64_t
+	if err := cCall(func() C.obx_err { return C.obx_query_prop_sum_int(pq.cQuery, &cResult, nil) }); err != nil {
+		return 0, err
+	}
+	return int64(cResult), nil
+}
+
+// MinUint finds the minimum value of the unsigned integer property across all objects matching the query.
+// The native API only provides signed aggregates, so the minimum is computed from the values read by FindUint*().
+func (pq *PropertyQuery) MinUint() (uint64, error) {
+	values, err := pq.uintAggregateValues("MinUint")
+	if err != nil || len(values) == 0 {
+		return 0, err
+	}
+
+	var result = values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result, nil
+}
+
+// MaxUint finds the maximum value of the unsigned integer property across all objects matching the query.
+// The native API only provides signed aggregates, so the maximum is computed from the values read by FindUint*().
+func (pq *PropertyQuery) MaxUint() (uint64, error) {
+	values, err := pq.uintAggregateValues("MaxUint")
+	if err != nil {
+		return 0, err
+	}
+
+	var result uint64
+	for _, value := range values {
+		if value > result {
+			result = value
+		}
+	}
+	return result, nil
+}
+
+// SumUint calculates the sum of the unsigned integer property across all objects matching the query.
+// The native API only provides signed aggregates, so the sum is computed from the values read by FindUint*().
+// Returns an error if the result doesn't fit into an uint64.
+func (pq *PropertyQuery) SumUint() (uint64, error) {
+	values, err := pq.uintAggregateValues("SumUint")
+	if err != nil {
+		return 0, err
+	}
+
+	var result uint64
+	for _, value := range values {
+		if result+value < result {
+			return 0, fmt.Errorf("sum of property %s overflows uint64", pq.property.Name)
+		}
+		result += value
+	}
+	return result, nil
+}
+
+// uintAggregateValues reads the (non-nil) values of the property for the unsigned aggregate functions
+func (pq *PropertyQuery) uintAggregateValues(method string) ([]uint64, error) {
+	if err := pq.checkAggregate(method); err != nil {
+		return nil, err
+	}
+
+	var result []uint64
+	switch pq.property.Type {
+	case PropertyTypeLong, PropertyTypeDate, PropertyTypeDateNano, PropertyTypeRelation:
+		return pq.FindUint64s(nil)
+	case PropertyTypeInt:
+		values, err := pq.FindUint32s(nil)
+		for _, value := range values {
+			result = append(result, uint64(value))
+		}
+		return result, err
+	case PropertyTypeShort, PropertyTypeChar:
+		values, err := pq.FindUint16s(nil)
+		for _, value := range values {
+			result = append(result, uint64(value))
+		}
+		return result, err
+	case PropertyTypeByte:
+		values, err := pq.FindUint8s(nil)
+		for _, value := range values {
+			result = append(result, uint64(value))
+		}
+		return result, err
+	default:
+		return nil, fmt.Errorf("%s() can't be used on property %s of type %d", method, pq.property.Name, pq.property.Type)
+	}
+}
+
+// FindStrings returns values of the string property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindStrings(valueIfNil *string) ([]string, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	var cValueIfNil *C.char
+	if valueIfNil != nil {
+		cValueIfNil = C.CString(*valueIfNil)
+		defer C.free(unsafe.Pointer(cValueIfNil))
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_strings(pq.cQuery, cValueIfNil)
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_string_array_free(cArray)
+
+	return cStringArrayToGo(cArray), nil
+}
+
+// FindInt64s returns values of the integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindInt64s(valueIfNil *int64) ([]int64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_int64s(pq.cQuery, (*C.int64_t)(unsafe.Pointer(valueIfNil)))
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_int64_array_free(cArray)
+
+	return cInt64ArrayToGo(cArray), nil
+}
+
+// FindInt32s returns values of the integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindInt32s(valueIfNil *int32) ([]int32, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_int32s(pq.cQuery, (*C.int32_t)(unsafe.Pointer(valueIfNil)))
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_int32_array_free(cArray)
+
+	return cInt32ArrayToGo(cArray), nil
+}
+
+// FindInt16s returns values of the integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindInt16s(valueIfNil *int16) ([]int16, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_int16s(pq.cQuery, (*C.int16_t)(unsafe.Pointer(valueIfNil)))
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_int16_array_free(cArray)
+
+	return cInt16ArrayToGo(cArray), nil
+}
+
+// FindInt8s returns values of the integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindInt8s(valueIfNil *int8) ([]int8, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_int8s(pq.cQuery, (*C.int8_t)(unsafe.Pointer(valueIfNil)))
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_int8_array_free(cArray)
+
+	return cInt8ArrayToGo(cArray), nil
+}
+
+// FindFloat64s returns values of the floating-point property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindFloat64s(valueIfNil *float64) ([]float64, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_doubles(pq.cQuery, (*C.double)(unsafe.Pointer(valueIfNil)))
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_double_array_free(cArray)
+
+	return cDoubleArrayToGo(cArray), nil
+}
+
+// FindFloat32s returns values of the floating-point property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindFloat32s(valueIfNil *float32) ([]float32, error) {
+	defer runtime.KeepAlive(pq)
+
+	if err := pq.check(); err != nil {
+		return nil, err
+	}
+
+	// for native calls/createError()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cArray = C.obx_query_prop_find_floats(pq.cQuery, (*C.float)(unsafe.Pointer(valueIfNil)))
+	if cArray == nil {
+		return nil, createError()
+	}
+	defer C.obx_float_array_free(cArray)
+
+	return cFloatArrayToGo(cArray), nil
+}
+
+// FindUint64s returns values of the unsigned integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindUint64s(valueIfNil *uint64) ([]uint64, error) {
+	values, err := pq.FindInt64s((*int64)(unsafe.Pointer(valueIfNil)))
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]uint64, len(values))
+	for i, value := range values {
+		result[i] = uint64(value)
+	}
+	return result, nil
+}
+
+// FindUint32s returns values of the unsigned integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindUint32s(valueIfNil *uint32) ([]uint32, error) {
+	values, err := pq.FindInt32s((*int32)(unsafe.Pointer(valueIfNil)))
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]uint32, len(values))
+	for i, value := range values {
+		result[i] = uint32(value)
+	}
+	return result, nil
+}
+
+// FindUint16s returns values of the unsigned integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindUint16s(valueIfNil *uint16) ([]uint16, error) {
+	values, err := pq.FindInt16s((*int16)(unsafe.Pointer(valueIfNil)))
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]uint16, len(values))
+	for i, value := range values {
+		result[i] = uint16(value)
+	}
+	return result, nil
+}
+
+// FindUint8s returns values of the unsigned integer property across all objects matching the query.
+// Pass a non-nil valueIfNil to use it in place of nil values; otherwise objects with nil values are skipped.
+func (pq *PropertyQuery) FindUint8s(valueIfNil *uint8) ([]uint8, error) {
+	values, err := pq.FindInt8s((*int8)(unsafe.Pointer(valueIfNil)))
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]uint8, len(values))
+	for i, value := range values {
+		result[i] = uint8(value)
+	}
+	return result, nil
+}
@@ -22,6 +22,7 @@ package objectbox
 */
 import "C"
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime"
@@ -34,15 +35,39 @@ import (
 // For example, you can find all people whose last name starts with an 'N':
 // 		box.Query(Person_.LastName.HasPrefix("N", false)).Find()
 // Note that Person_ is a struct generated by ObjectBox allowing to conveniently reference properties.
+//
+// A query can be built once and reused with different arguments - change condition values using the Set*Params()
+// methods (identifying the condition by its property or by an Alias()) and Offset()/Limit() before executing it.
+// The order of a query is fixed when it's built; build another query if a different order is needed.
 type Query struct {
 	entity          *entity
 	objectBox       *ObjectBox
 	box             *Box
 	cQuery          *C.OBX_query
 	closeMutex      sync.Mutex
+	offset          uint64
+	limit           uint64
 	offsetErr       error
 	limitErr        error
 	linkedEntityIds []TypeId
+	structure       *queryStructure
+
+	// keyset pagination state, see FindPage()
+	conditions     []Condition
+	params         map[paramKey]func(*Query) error
+	pageQuery      *Query
+	firstPageQuery *Query
+	afterId        uint64
+	afterValue     interface{}
+	afterErr       error
+
+	// related objects loading, see Eager() and NoEager()
+	eager    []*RelationToMany
+	noEager  bool
+	eagerErr error
+
+	// property queries created from this query, closed together with it; guarded by closeMutex
+	propertyQueries map[*PropertyQuery]bool
 }
 
 // Close frees (native) resources held by this Query.
@@ -51,14 +76,35 @@ func (query *Query) Close() error {
 	query.closeMutex.Lock()
 	defer query.closeMutex.Unlock()
 
+	// close all queries, even if one of them fails, and return the first error;
+	// native property queries must be closed before the query they were created from
+	var err error
+	for pq := range query.propertyQueries {
+		if closeErr := pq.closeLocked(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	query.propertyQueries = nil
+
+	for _, pageQuery := range []**Query{&query.pageQuery, &query.firstPageQuery} {
+		if *pageQuery != nil {
+			if closeErr := (*pageQuery).Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+			*pageQuery = nil
+		}
+	}
+
 	if query.cQuery != nil {
-		return cCall(func() C.obx_err {
+		if closeErr := cCall(func() C.obx_err {
 			var err = C.obx_query_close(query.cQuery)
 			query.cQuery = nil
 			return err
-		})
+		}); closeErr != nil && err == nil {
+			err = closeErr
+		}
 	}
-	return nil
+	return err
 }
 
 func queryFinalizer(query *Query) {
@@ -78,10 +124,14 @@ func (query *Query) installFinalizer() {
 func (query *Query) check() error {
 	if query.cQuery == nil {
 		return errors.New("illegal state; query was closed")
+	} else if err := query.box.checkTx(); err != nil {
+		return err
 	} else if query.limitErr != nil {
 		return query.limitErr
 	} else if query.offsetErr != nil {
 		return query.offsetErr
+	} else if query.eagerErr != nil {
+		return query.eagerErr
 	}
 
 	return nil
@@ -90,40 +140,147 @@ func (query *Query) check() error {
 // Find returns all objects matching the query
 func (query *Query) Find() (objects interface{}, err error) {
 	defer runtime.KeepAlive(query)
+	defer query.logExecution("find")(&err)
 
 	if err := query.check(); err != nil {
 		return nil, err
 	}
 
+	box, err := query.readBox()
+	if err != nil {
+		return nil, err
+	}
+
 	const existingOnly = true
-	if supportsResultArray {
-		var cFn = func() *C.OBX_bytes_array {
-			return C.obx_query_find(query.cQuery)
+	objects, err = query.readWithEager(func() (interface{}, error) {
+		if supportsResultArray {
+			var cFn = func() *C.OBX_bytes_array {
+				return C.obx_query_find(query.cQuery)
+			}
+			return box.readManyObjects(existingOnly, cFn)
 		}
-		return query.box.readManyObjects(existingOnly, cFn)
+
+		var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
+			return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
+		}
+		return box.readUsingVisitor(context.Background(), existingOnly, cFn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// FindCtx is like Find() but reads the objects one by one, checking the given context in between.
+// The read is aborted and the context's error is returned as soon as the context is cancelled or its deadline exceeded.
+func (query *Query) FindCtx(ctx context.Context) (objects interface{}, err error) {
+	defer runtime.KeepAlive(query)
+	defer query.logExecution("find")(&err)
+
+	if err := query.check(); err != nil {
+		return nil, err
 	}
 
+	box, err := query.readBox()
+	if err != nil {
+		return nil, err
+	}
+
+	const existingOnly = true
 	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
 		return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
 	}
-	return query.box.readUsingVisitor(existingOnly, cFn)
+	objects, err = query.readWithEager(func() (interface{}, error) {
+		return box.readUsingVisitor(ctx, existingOnly, cFn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// ForEach streams all objects matching the query to the given callback, one by one, without reading them all into
+// memory first. The iteration stops early if the callback returns false or an error; the error is passed-through as
+// the output error.
+//
+// The callback is executed inside a read transaction; don't start a write transaction (e.g. Put) from inside it.
+// The object passed to the callback should be cast to the appropriate type.
+func (query *Query) ForEach(fn func(object interface{}) (bool, error)) error {
+	return query.ForEachCtx(context.Background(), fn)
+}
+
+// ForEachCtx is like ForEach() but aborts the iteration as soon as the given context is cancelled or its deadline
+// exceeded, returning the context's error.
+func (query *Query) ForEachCtx(ctx context.Context, fn func(object interface{}) (bool, error)) (err error) {
+	defer runtime.KeepAlive(query)
+	defer query.logExecution("forEach")(&err)
+
+	if err := query.check(); err != nil {
+		return err
+	}
+
+	box, err := query.readBox()
+	if err != nil {
+		return err
+	}
+
+	var cFn = func(visitorArg unsafe.Pointer) C.obx_err {
+		return C.obx_query_visit(query.cQuery, dataVisitor, visitorArg)
+	}
+	if len(query.eager) == 0 {
+		return box.visitObjects(ctx, fn, cFn)
+	}
+
+	// objects are passed to the callback in chunks, after reading the Eager() relations of the whole chunk at once
+	const chunkSize = 100
+	var chunk = make([]interface{}, 0, chunkSize)
+	var flush = func() (bool, error) {
+		defer func() { chunk = chunk[:0] }()
+		if len(chunk) == 0 {
+			return true, nil
+		} else if err := query.loadEager(chunk); err != nil {
+			return false, err
+		}
+		for _, object := range chunk {
+			if next, err := fn(object); !next || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	return query.objectBox.RunInReadTx(func() error {
+		var err = box.visitObjects(ctx, func(object interface{}) (bool, error) {
+			if chunk = append(chunk, object); len(chunk) < chunkSize {
+				return true, nil
+			}
+			return flush()
+		}, cFn)
+		if err == nil {
+			_, err = flush()
+		}
+		return err
+	})
 }
 
 // Offset defines the index of the first object to process (how many objects to skip)
 func (query *Query) Offset(offset uint64) *Query {
+	query.offset = offset
 	query.offsetErr = cCall(func() C.obx_err { return C.obx_query_offset(query.cQuery, C.uint64_t(offset)) })
 	return query
 }
 
 // Limit sets the number of elements to process by the query
 func (query *Query) Limit(limit uint64) *Query {
+	query.limit = limit
 	query.limitErr = cCall(func() C.obx_err { return C.obx_query_limit(query.cQuery, C.uint64_t(limit)) })
 	return query
 }
 
 // FindIds returns IDs of all objects matching the query
-func (query *Query) FindIds() ([]uint64, error) {
+func (query *Query) FindIds() (ids []uint64, err error) {
 	defer runtime.KeepAlive(query)
+	defer query.logExecution("findIds")(&err)
 
 	if err := query.check(); err != nil {
 		return nil, err
@@ -136,7 +293,9 @@ func (query *Query) FindIds() ([]uint64, error) {
 
 // Count returns the number of objects matching the query.
 // Currently can't be used in combination with Offset().
-func (query *Query) Count() (uint64, error) {
+func (query *Query) Count() (count uint64, err error) {
+	defer query.logExecution("count")(&err)
+
 	if err := query.check(); err != nil {
 		return 0, err
 	}
@@ -152,12 +311,23 @@ func (query *Query) Count() (uint64, error) {
 // Remove permanently deletes all objects matching the query from the database.
 // Currently can't be used in combination with Offset() or Limit().
 func (query *Query) Remove() (count uint64, err error) {
+	defer query.logExecution("remove")(&err)
+
 	if err := query.check(); err != nil {
 		return 0, err
 	}
 
 	var cResult C.uint64_t
-	if err := cCall(func() C.obx_err { return C.obx_query_remove(query.cQuery, &cResult) }); err != nil {
+	var remove = func() error {
+		return cCall(func() C.obx_err { return C.obx_query_remove(query.cQuery, &cResult) })
+	}
+
+	if len(query.entity.incomingRelations) > 0 {
+		err = query.box.removeWithPolicies(query.FindIds, remove)
+	} else {
+		err = remove()
+	}
+	if err != nil {
 		return 0, err
 	}
 
@@ -213,8 +383,10 @@ type propertyOrAlias interface {
 }
 
 // SetStringParams changes query parameter values on the given property
-func (query *Query) SetStringParams(identifier propertyOrAlias, values ...string) error {
+func (query *Query) SetStringParams(identifier propertyOrAlias, values ...string) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = append([]string(nil), values...) // the caller may reuse the slice
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetStringParams(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -246,8 +418,10 @@ func (query *Query) SetStringParams(identifier propertyOrAlias, values ...string
 }
 
 // SetStringParamsIn changes query parameter values on the given property
-func (query *Query) SetStringParamsIn(identifier propertyOrAlias, values ...string) error {
+func (query *Query) SetStringParamsIn(identifier propertyOrAlias, values ...string) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = append([]string(nil), values...) // the caller may reuse the slice
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetStringParamsIn(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -275,8 +449,10 @@ func (query *Query) SetStringParamsIn(identifier propertyOrAlias, values ...stri
 }
 
 // SetInt64Params changes query parameter values on the given property
-func (query *Query) SetInt64Params(identifier propertyOrAlias, values ...int64) error {
+func (query *Query) SetInt64Params(identifier propertyOrAlias, values ...int64) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = append([]int64(nil), values...) // the caller may reuse the slice
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetInt64Params(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -313,8 +489,10 @@ func (query *Query) SetInt64Params(identifier propertyOrAlias, values ...int64)
 }
 
 // SetInt64ParamsIn changes query parameter values on the given property
-func (query *Query) SetInt64ParamsIn(identifier propertyOrAlias, values ...int64) error {
+func (query *Query) SetInt64ParamsIn(identifier propertyOrAlias, values ...int64) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = append([]int64(nil), values...) // the caller may reuse the slice
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetInt64ParamsIn(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -339,8 +517,10 @@ func (query *Query) SetInt64ParamsIn(identifier propertyOrAlias, values ...int64
 }
 
 // SetInt32ParamsIn changes query parameter values on the given property
-func (query *Query) SetInt32ParamsIn(identifier propertyOrAlias, values ...int32) error {
+func (query *Query) SetInt32ParamsIn(identifier propertyOrAlias, values ...int32) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = append([]int32(nil), values...) // the caller may reuse the slice
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetInt32ParamsIn(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -365,8 +545,10 @@ func (query *Query) SetInt32ParamsIn(identifier propertyOrAlias, values ...int32
 }
 
 // SetFloat64Params changes query parameter values on the given property
-func (query *Query) SetFloat64Params(identifier propertyOrAlias, values ...float64) error {
+func (query *Query) SetFloat64Params(identifier propertyOrAlias, values ...float64) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = append([]float64(nil), values...) // the caller may reuse the slice
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetFloat64Params(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -404,8 +586,13 @@ func (query *Query) SetFloat64Params(identifier propertyOrAlias, values ...float
 }
 
 // SetBytesParams changes query parameter values on the given property
-func (query *Query) SetBytesParams(identifier propertyOrAlias, values ...[]byte) error {
+func (query *Query) SetBytesParams(identifier propertyOrAlias, values ...[]byte) (err error) {
 	defer runtime.KeepAlive(query)
+	var remembered = make([][]byte, len(values)) // the caller may reuse the slices
+	for i, value := range values {
+		remembered[i] = append([]byte(nil), value...)
+	}
+	defer query.rememberParams(identifier, &err, func(q *Query) error { return q.SetBytesParams(identifier, remembered...) })
 
 	if err := query.checkIdentifier(identifier); err != nil {
 		return err
@@ -431,3 +618,52 @@ func (query *Query) SetBytesParams(identifier propertyOrAlias, values ...[]byte)
 		return C.obx_query_param_bytes(query.cQuery, C.obx_schema_id(identifier.entityId()), C.obx_schema_id(identifier.propertyId()), cBytesPtr(values[0]), C.size_t(len(values[0])))
 	})
 }
+
+// SetBoolParams changes query parameter value on the given bool property, e.g. a condition created by PropertyBool.Equals()
+func (query *Query) SetBoolParams(identifier propertyOrAlias, value bool) error {
+	if value {
+		return query.SetInt64Params(identifier, 1)
+	}
+	return query.SetInt64Params(identifier, 0)
+}
+
+// SetUint64Params changes query parameter values on the given unsigned integer property, relation or ID.
+// Values above math.MaxInt64 are passed to the database unchanged, i.e. they're compared as unsigned.
+func (query *Query) SetUint64Params(identifier propertyOrAlias, values ...uint64) error {
+	if len(values) > 2 {
+		return fmt.Errorf("too many values given")
+	}
+
+	var signed = make([]int64, len(values))
+	for i, value := range values {
+		signed[i] = int64(value)
+	}
+	return query.SetInt64Params(identifier, signed...)
+}
+
+// SetUint64ParamsIn changes query parameter values on the given unsigned integer property, relation or ID
+func (query *Query) SetUint64ParamsIn(identifier propertyOrAlias, values ...uint64) error {
+	var signed = make([]int64, len(values))
+	for i, value := range values {
+		signed[i] = int64(value)
+	}
+	return query.SetInt64ParamsIn(identifier, signed...)
+}
+
+// SetFloat32Params changes query parameter values on the given float32 property
+func (query *Query) SetFloat32Params(identifier propertyOrAlias, values ...float32) error {
+	if len(values) > 2 {
+		return fmt.Errorf("too many values given")
+	}
+
+	var doubles = make([]float64, len(values))
+	for i, value := range values {
+		doubles[i] = float64(value)
+	}
+	return query.SetFloat64Params(identifier, doubles...)
+}
+
+// SetStringVectorParams changes the query parameter value of a PropertyStringVector.Contains() condition
+func (query *Query) SetStringVectorParams(identifier propertyOrAlias, value string) error {
+	return query.SetStringParams(identifier, value)
+}
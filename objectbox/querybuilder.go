@@ -37,6 +37,11 @@ type QueryBuilder struct {
 	typeId        TypeId
 	innerBuilders []*QueryBuilder
 	orderFlags    map[TypeId]C.OBXOrderFlags
+	orderIds      []TypeId // properties in orderFlags, in the order of the Order*() calls
+
+	// properties used in conditions and the description of the link (inner builders only), see Query.Describe()
+	conditionProperties []TypeId
+	link                string
 
 	// The first error that occurred during a any of the calls on the query builder
 	Err error
@@ -106,8 +111,9 @@ func (qb *QueryBuilder) Close() error {
 
 // Build is called internally
 func (qb *QueryBuilder) Build(box *Box) (*Query, error) {
-	for propertyId, orderFlags := range qb.orderFlags {
-		qb.order(C.obx_schema_id(propertyId), orderFlags)
+	// the first order property has the highest precedence
+	for _, propertyId := range qb.orderIds {
+		qb.order(C.obx_schema_id(propertyId), qb.orderFlags[propertyId])
 	}
 
 	if qb.Err != nil {
@@ -132,6 +138,7 @@ func (qb *QueryBuilder) Build(box *Box) (*Query, error) {
 
 	// search all inner builders recursively and collect linked entity IDs
 	qb.setQueryLinkedEntityIds(query)
+	query.structure = qb.structure()
 
 	return query, nil
 }
@@ -176,11 +183,11 @@ func (qb *QueryBuilder) LinkOneToMany(relation *RelationToOne, conditions []Cond
 		// if property belongs to the entity of the "main" query builder & target is another entity, it's a link
 		// log.Printf("QB %p creating link to entity %d over property %d", qb, relation.Target.Id, relation.Property.Id)
 		iqb = qb.newInnerBuilder(relation.Target.Id, C.obx_qb_link_property(qb.cqb, cRelPropertyId))
+		qb.describeLink(iqb, "link to %s over property %s", relation.Target.Id,
+			qb.propertyName(relation.Property.Entity.Id, relation.Property.Id))
 	} else if relation.Property.Entity.Id != qb.typeId && relation.Target.Id == qb.typeId {
 		// if property is not from the same entity as this query builder but the target is, it's a backlink
-		// log.Printf("QB %p creating backlink from entity %d over property %d", qb, relation.Property.Entity.Id, relation.Property.Id)
-		cInnerQB := C.obx_qb_backlink_property(qb.cqb, C.obx_schema_id(relation.Property.Entity.Id), cRelPropertyId)
-		iqb = qb.newInnerBuilder(relation.Property.Entity.Id, cInnerQB)
+		iqb = qb.newBacklinkOneToMany(relation)
 	} else {
 		return errors.New("relation not recognized as either link or backlink")
 	}
@@ -192,6 +199,37 @@ func (qb *QueryBuilder) LinkOneToMany(relation *RelationToOne, conditions []Cond
 	return iqb.applyConditions(conditions)
 }
 
+// LinkBacklinkOneToMany is called internally
+func (qb *QueryBuilder) LinkBacklinkOneToMany(relation *RelationToOne, conditions []Condition) error {
+	if qb.Err != nil {
+		return qb.Err
+	}
+
+	if relation.Target.Id != qb.typeId {
+		return fmt.Errorf("backlink relation target entity %d doesn't match the queried entity %d", relation.Target.Id, qb.typeId)
+	}
+
+	// for native calls/createError() in newInnerBuilder
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var iqb = qb.newBacklinkOneToMany(relation)
+	if iqb == nil {
+		return qb.Err // this has been set by newInnerBuilder()
+	}
+
+	return iqb.applyConditions(conditions)
+}
+
+func (qb *QueryBuilder) newBacklinkOneToMany(relation *RelationToOne) *QueryBuilder {
+	// log.Printf("QB %p creating backlink from entity %d over property %d", qb, relation.Property.Entity.Id, relation.Property.Id)
+	cInnerQB := C.obx_qb_backlink_property(qb.cqb, C.obx_schema_id(relation.Property.Entity.Id), C.obx_schema_id(relation.Property.Id))
+	iqb := qb.newInnerBuilder(relation.Property.Entity.Id, cInnerQB)
+	qb.describeLink(iqb, "backlink from %s over property %s", relation.Property.Entity.Id,
+		qb.propertyName(relation.Property.Entity.Id, relation.Property.Id))
+	return iqb
+}
+
 // LinkManyToMany is called internally
 func (qb *QueryBuilder) LinkManyToMany(relation *RelationToMany, conditions []Condition) error {
 	if qb.Err != nil {
@@ -209,9 +247,9 @@ func (qb *QueryBuilder) LinkManyToMany(relation *RelationToMany, conditions []Co
 	if relation.Source.Id == qb.typeId && relation.Target.Id != qb.typeId {
 		// log.Printf("QB %p creating link to entity %d over relation %d", qb, relation.Target.Id, relation.Id)
 		iqb = qb.newInnerBuilder(relation.Target.Id, C.obx_qb_link_standalone(qb.cqb, C.obx_schema_id(relation.Id)))
+		qb.describeLink(iqb, "many-to-many link to %s over relation %d", relation.Target.Id, relation.Id)
 	} else if relation.Source.Id != qb.typeId && relation.Target.Id == qb.typeId {
-		// log.Printf("QB %p creating backlink from entity %d over relation %d", qb, relation.Source.Id, relation.Id)
-		iqb = qb.newInnerBuilder(relation.Source.Id, C.obx_qb_backlink_standalone(qb.cqb, C.obx_schema_id(relation.Id)))
+		iqb = qb.newBacklinkManyToMany(relation)
 	} else {
 		return errors.New("relation not recognized as either link or backlink")
 	}
@@ -222,6 +260,35 @@ func (qb *QueryBuilder) LinkManyToMany(relation *RelationToMany, conditions []Co
 	return iqb.applyConditions(conditions)
 }
 
+// LinkBacklinkManyToMany is called internally
+func (qb *QueryBuilder) LinkBacklinkManyToMany(relation *RelationToMany, conditions []Condition) error {
+	if qb.Err != nil {
+		return qb.Err
+	}
+
+	if relation.Target.Id != qb.typeId {
+		return fmt.Errorf("backlink relation target entity %d doesn't match the queried entity %d", relation.Target.Id, qb.typeId)
+	}
+
+	// for native calls/createError() in newInnerBuilder
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var iqb = qb.newBacklinkManyToMany(relation)
+	if iqb == nil {
+		return qb.Err // this has been set by newInnerBuilder()
+	}
+
+	return iqb.applyConditions(conditions)
+}
+
+func (qb *QueryBuilder) newBacklinkManyToMany(relation *RelationToMany) *QueryBuilder {
+	// log.Printf("QB %p creating backlink from entity %d over relation %d", qb, relation.Source.Id, relation.Id)
+	iqb := qb.newInnerBuilder(relation.Source.Id, C.obx_qb_backlink_standalone(qb.cqb, C.obx_schema_id(relation.Id)))
+	qb.describeLink(iqb, "many-to-many backlink from %s over relation %d", relation.Source.Id, relation.Id)
+	return iqb
+}
+
 func (qb *QueryBuilder) order(propertyId C.obx_schema_id, flags C.OBXOrderFlags) {
 	if qb.Err == nil {
 		qb.Err = cCall(func() C.obx_err {
@@ -234,6 +301,9 @@ func (qb *QueryBuilder) order(propertyId C.obx_schema_id, flags C.OBXOrderFlags)
 // if value is true, the flag is set, otherwise the flag is cleared (unset)
 func (qb *QueryBuilder) setOrderFlag(property *BaseProperty, flag C.OBXOrderFlags, value bool) error {
 	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+		if _, exists := qb.orderFlags[property.Id]; !exists {
+			qb.orderIds = append(qb.orderIds, property.Id)
+		}
 		if value {
 			// set the flag
 			qb.orderFlags[property.Id] = qb.orderFlags[property.Id] | flag
@@ -296,6 +366,21 @@ func (qb *QueryBuilder) checkEntityId(entityId TypeId) bool {
 	return false
 }
 
+// checkProperty verifies the property belongs to the queried entity and records its use in a condition
+func (qb *QueryBuilder) checkProperty(property *BaseProperty) bool {
+	if !qb.checkEntityId(property.Entity.Id) {
+		return false
+	}
+
+	for _, id := range qb.conditionProperties {
+		if id == property.Id {
+			return true
+		}
+	}
+	qb.conditionProperties = append(qb.conditionProperties, property.Id)
+	return true
+}
+
 func (qb *QueryBuilder) getConditionId(cid C.obx_qb_cond) ConditionId {
 	if cid == 0 {
 		// we only need to check & store the error if cid is 0, otherwise there can't be any error
@@ -344,7 +429,7 @@ func (qb *QueryBuilder) All(ids []ConditionId) (ConditionId, error) {
 func (qb *QueryBuilder) IsNil(property *BaseProperty) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_null(qb.cqb, C.obx_schema_id(property.Id)))
 	}
 
@@ -355,7 +440,7 @@ func (qb *QueryBuilder) IsNil(property *BaseProperty) (ConditionId, error) {
 func (qb *QueryBuilder) IsNotNil(property *BaseProperty) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_not_null(qb.cqb, C.obx_schema_id(property.Id)))
 	}
 
@@ -366,7 +451,7 @@ func (qb *QueryBuilder) IsNotNil(property *BaseProperty) (ConditionId, error) {
 func (qb *QueryBuilder) StringEquals(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		cid = qb.getConditionId(C.obx_qb_equals_string(qb.cqb, C.obx_schema_id(property.Id), cvalue, C.bool(caseSensitive)))
@@ -379,7 +464,7 @@ func (qb *QueryBuilder) StringEquals(property *BaseProperty, value string, caseS
 func (qb *QueryBuilder) StringIn(property *BaseProperty, values []string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if len(values) > 0 {
 			cStringArray := goStringArrayToC(values)
 			defer cStringArray.free()
@@ -396,7 +481,7 @@ func (qb *QueryBuilder) StringIn(property *BaseProperty, values []string, caseSe
 func (qb *QueryBuilder) StringContains(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		cid = qb.getConditionId(C.obx_qb_contains_string(qb.cqb, C.obx_schema_id(property.Id), cvalue, C.bool(caseSensitive)))
@@ -409,7 +494,7 @@ func (qb *QueryBuilder) StringContains(property *BaseProperty, value string, cas
 func (qb *QueryBuilder) StringHasPrefix(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		cid = qb.getConditionId(C.obx_qb_starts_with_string(qb.cqb, C.obx_schema_id(property.Id), cvalue, C.bool(caseSensitive)))
@@ -422,7 +507,7 @@ func (qb *QueryBuilder) StringHasPrefix(property *BaseProperty, value string, ca
 func (qb *QueryBuilder) StringHasSuffix(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		cid = qb.getConditionId(C.obx_qb_ends_with_string(qb.cqb, C.obx_schema_id(property.Id), cvalue, C.bool(caseSensitive)))
@@ -435,7 +520,7 @@ func (qb *QueryBuilder) StringHasSuffix(property *BaseProperty, value string, ca
 func (qb *QueryBuilder) StringNotEquals(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		cid = qb.getConditionId(C.obx_qb_not_equals_string(qb.cqb, C.obx_schema_id(property.Id), cvalue, C.bool(caseSensitive)))
@@ -448,7 +533,7 @@ func (qb *QueryBuilder) StringNotEquals(property *BaseProperty, value string, ca
 func (qb *QueryBuilder) StringGreater(property *BaseProperty, value string, caseSensitive bool, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		if withEqual {
@@ -465,7 +550,7 @@ func (qb *QueryBuilder) StringGreater(property *BaseProperty, value string, case
 func (qb *QueryBuilder) StringLess(property *BaseProperty, value string, caseSensitive bool, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		if withEqual {
@@ -482,7 +567,7 @@ func (qb *QueryBuilder) StringLess(property *BaseProperty, value string, caseSen
 func (qb *QueryBuilder) StringVectorContains(property *BaseProperty, value string, caseSensitive bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cvalue := C.CString(value)
 		defer C.free(unsafe.Pointer(cvalue))
 		cid = qb.getConditionId(C.obx_qb_any_equals_string(qb.cqb, C.obx_schema_id(property.Id), cvalue, C.bool(caseSensitive)))
@@ -495,7 +580,7 @@ func (qb *QueryBuilder) StringVectorContains(property *BaseProperty, value strin
 func (qb *QueryBuilder) IntBetween(property *BaseProperty, value1 int64, value2 int64) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_between_2ints(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value1), C.int64_t(value2)))
 	}
 
@@ -506,7 +591,7 @@ func (qb *QueryBuilder) IntBetween(property *BaseProperty, value1 int64, value2
 func (qb *QueryBuilder) IntEqual(property *BaseProperty, value int64) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_equals_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
 	}
 
@@ -517,7 +602,7 @@ func (qb *QueryBuilder) IntEqual(property *BaseProperty, value int64) (Condition
 func (qb *QueryBuilder) IntNotEqual(property *BaseProperty, value int64) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_not_equals_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
 	}
 
@@ -528,7 +613,7 @@ func (qb *QueryBuilder) IntNotEqual(property *BaseProperty, value int64) (Condit
 func (qb *QueryBuilder) IntGreater(property *BaseProperty, value int64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_greater_or_equal_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
 		} else {
@@ -543,7 +628,7 @@ func (qb *QueryBuilder) IntGreater(property *BaseProperty, value int64, withEqua
 func (qb *QueryBuilder) IntLess(property *BaseProperty, value int64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_less_or_equal_int(qb.cqb, C.obx_schema_id(property.Id), C.int64_t(value)))
 		} else {
@@ -558,7 +643,7 @@ func (qb *QueryBuilder) IntLess(property *BaseProperty, value int64, withEqual b
 func (qb *QueryBuilder) Int64In(property *BaseProperty, values []int64) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_in_int64s(qb.cqb, C.obx_schema_id(property.Id), goInt64ArrayToC(values), C.size_t(len(values))))
 	}
 
@@ -569,7 +654,7 @@ func (qb *QueryBuilder) Int64In(property *BaseProperty, values []int64) (Conditi
 func (qb *QueryBuilder) Int64NotIn(property *BaseProperty, values []int64) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_not_in_int64s(qb.cqb, C.obx_schema_id(property.Id), goInt64ArrayToC(values), C.size_t(len(values))))
 	}
 
@@ -580,7 +665,7 @@ func (qb *QueryBuilder) Int64NotIn(property *BaseProperty, values []int64) (Cond
 func (qb *QueryBuilder) Int32In(property *BaseProperty, values []int32) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_in_int32s(qb.cqb, C.obx_schema_id(property.Id), goInt32ArrayToC(values), C.size_t(len(values))))
 	}
 
@@ -591,7 +676,7 @@ func (qb *QueryBuilder) Int32In(property *BaseProperty, values []int32) (Conditi
 func (qb *QueryBuilder) Int32NotIn(property *BaseProperty, values []int32) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_not_in_int32s(qb.cqb, C.obx_schema_id(property.Id), goInt32ArrayToC(values), C.size_t(len(values))))
 	}
 
@@ -602,7 +687,7 @@ func (qb *QueryBuilder) Int32NotIn(property *BaseProperty, values []int32) (Cond
 func (qb *QueryBuilder) DoubleGreater(property *BaseProperty, value float64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_greater_or_equal_double(qb.cqb, C.obx_schema_id(property.Id), C.double(value)))
 		} else {
@@ -617,7 +702,7 @@ func (qb *QueryBuilder) DoubleGreater(property *BaseProperty, value float64, wit
 func (qb *QueryBuilder) DoubleLess(property *BaseProperty, value float64, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_less_or_equal_double(qb.cqb, C.obx_schema_id(property.Id), C.double(value)))
 		} else {
@@ -632,7 +717,7 @@ func (qb *QueryBuilder) DoubleLess(property *BaseProperty, value float64, withEq
 func (qb *QueryBuilder) DoubleBetween(property *BaseProperty, valueA float64, valueB float64) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_between_2doubles(qb.cqb, C.obx_schema_id(property.Id), C.double(valueA), C.double(valueB)))
 	}
 
@@ -643,7 +728,7 @@ func (qb *QueryBuilder) DoubleBetween(property *BaseProperty, valueA float64, va
 func (qb *QueryBuilder) BytesEqual(property *BaseProperty, value []byte) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		cid = qb.getConditionId(C.obx_qb_equals_bytes(qb.cqb, C.obx_schema_id(property.Id), cBytesPtr(value), C.size_t(len(value))))
 	}
 
@@ -654,7 +739,7 @@ func (qb *QueryBuilder) BytesEqual(property *BaseProperty, value []byte) (Condit
 func (qb *QueryBuilder) BytesGreater(property *BaseProperty, value []byte, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_greater_or_equal_bytes(qb.cqb, C.obx_schema_id(property.Id), cBytesPtr(value), C.size_t(len(value))))
 		} else {
@@ -669,7 +754,7 @@ func (qb *QueryBuilder) BytesGreater(property *BaseProperty, value []byte, withE
 func (qb *QueryBuilder) BytesLess(property *BaseProperty, value []byte, withEqual bool) (ConditionId, error) {
 	var cid ConditionId
 
-	if qb.Err == nil && qb.checkEntityId(property.Entity.Id) {
+	if qb.Err == nil && qb.checkProperty(property) {
 		if withEqual {
 			cid = qb.getConditionId(C.obx_qb_less_or_equal_bytes(qb.cqb, C.obx_schema_id(property.Id), cBytesPtr(value), C.size_t(len(value))))
 		} else {
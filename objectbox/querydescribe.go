@@ -0,0 +1,166 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// queryStructure is a snapshot of the query builder state, see Query.Describe()
+type queryStructure struct {
+	entity              *entity
+	conditionProperties []TypeId
+	orderFlags          map[TypeId]C.OBXOrderFlags
+	orderIds            []TypeId // properties in orderFlags, in the order of the Order*() calls
+	link                string
+	links               []*queryStructure
+}
+
+func (qb *QueryBuilder) structure() *queryStructure {
+	var result = &queryStructure{
+		entity:              qb.objectBox.getEntityById(qb.typeId),
+		conditionProperties: qb.conditionProperties,
+		orderFlags:          qb.orderFlags,
+		orderIds:            qb.orderIds,
+		link:                qb.link,
+	}
+	for _, iqb := range qb.innerBuilders {
+		result.links = append(result.links, iqb.structure())
+	}
+	return result
+}
+
+// describeLink sets the description of the inner builder's link; entityId is formatted as the entity name
+func (qb *QueryBuilder) describeLink(iqb *QueryBuilder, format string, entityId TypeId, arg interface{}) {
+	if iqb != nil {
+		iqb.link = fmt.Sprintf(format, qb.objectBox.getEntityById(entityId).name, arg)
+	}
+}
+
+func (qb *QueryBuilder) propertyName(entityId TypeId, propertyId TypeId) string {
+	return qb.objectBox.getEntityById(entityId).propertyName(propertyId)
+}
+
+// propertyName returns the name of the property with the given ID or the ID itself if not found (invalid binding)
+func (entity *entity) propertyName(id TypeId) string {
+	for _, property := range entity.properties {
+		if property.Id == id {
+			return property.Name
+		}
+	}
+	return fmt.Sprintf("#%d", id)
+}
+
+// Describe returns a human readable description of the query, useful to diagnose slow queries: the conditions with
+// their current parameters (as described by the native library), the order, the linked entities (relations) and the
+// indexed properties used in conditions. Note that the native library decides which of the indexes is actually used.
+func (query *Query) Describe() (string, error) {
+	if err := query.check(); err != nil {
+		return "", err
+	}
+
+	// no need to free, it's handled by the cQuery internally
+	var description = C.GoString(C.obx_query_describe(query.cQuery))
+	var params = C.GoString(C.obx_query_describe_params(query.cQuery))
+	runtime.KeepAlive(query)
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSpace(description))
+	sb.WriteString("\nParameters: ")
+	sb.WriteString(strings.TrimSpace(params))
+
+	if order := query.structure.describeOrder(); len(order) > 0 {
+		sb.WriteString("\nOrder: ")
+		sb.WriteString(order)
+	}
+
+	if len(query.structure.links) > 0 {
+		sb.WriteString("\nLinks:")
+		query.structure.describeLinks(&sb, "  ")
+	}
+
+	sb.WriteString("\nIndexed properties in conditions: ")
+	if indexed := query.structure.indexedConditionProperties(); len(indexed) > 0 {
+		sb.WriteString(strings.Join(indexed, ", "))
+	} else {
+		sb.WriteString("none")
+	}
+
+	return sb.String(), nil
+}
+
+// describeOrder lists the order properties by precedence, i.e. in the order of the Order*() calls
+func (structure *queryStructure) describeOrder() string {
+	var result []string
+	for _, id := range structure.orderIds {
+		var flags = structure.orderFlags[id]
+		var details []string
+		if flags&C.OBXOrderFlags_DESCENDING != 0 {
+			details = append(details, "descending")
+		} else {
+			details = append(details, "ascending")
+		}
+		if flags&C.OBXOrderFlags_CASE_SENSITIVE != 0 {
+			details = append(details, "case sensitive")
+		}
+		if flags&C.OBXOrderFlags_NULLS_LAST != 0 {
+			details = append(details, "nil last")
+		}
+		if flags&C.OBXOrderFlags_NULLS_ZERO != 0 {
+			details = append(details, "nil as zero")
+		}
+		result = append(result, structure.entity.propertyName(id)+" ("+strings.Join(details, ", ")+")")
+	}
+	return strings.Join(result, ", ")
+}
+
+func (structure *queryStructure) describeLinks(sb *strings.Builder, indent string) {
+	for _, link := range structure.links {
+		sb.WriteString("\n" + indent + link.link)
+		if len(link.conditionProperties) > 0 {
+			var names = make([]string, len(link.conditionProperties))
+			for i, id := range link.conditionProperties {
+				names[i] = link.entity.propertyName(id)
+			}
+			sb.WriteString(", conditions on " + strings.Join(names, ", "))
+		}
+		link.describeLinks(sb, indent+"  ")
+	}
+}
+
+// indexedConditionProperties lists the indexed properties used in conditions, including those of linked entities
+func (structure *queryStructure) indexedConditionProperties() []string {
+	var result []string
+	for _, id := range structure.conditionProperties {
+		for _, property := range structure.entity.properties {
+			if property.Id == id && property.IndexId != 0 {
+				result = append(result, structure.entity.name+"."+property.Name)
+			}
+		}
+	}
+	for _, link := range structure.links {
+		result = append(result, link.indexedConditionProperties()...)
+	}
+	return result
+}
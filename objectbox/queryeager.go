@@ -0,0 +1,251 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ObjectBindingRelations is an optional extension of ObjectBinding, allowing queries to control which related objects
+// are read, see Query.Eager() and Query.NoEager(). Bindings generated by the current objectbox-generator don't implement
+// it, it can be added in a separate (hand-written) file of the same package. Without it, Eager() assigns the relation
+// targets to the only struct field of the matching slice type and NoEager() is only available on entities without
+// relations.
+type ObjectBindingRelations interface {
+	// LoadNoEager is like Load() but doesn't read any related objects: to-one relation fields are left empty and
+	// only the target ID is kept where the field type allows it (e.g. ToOne); to-many relation fields are left nil.
+	LoadNoEager(ob *ObjectBox, bytes []byte) (interface{}, error)
+
+	// SetRelated assigns the target objects of a standalone (many-to-many) relation to the field of the source object.
+	// The targets are a slice created by the target entity binding's MakeSlice().
+	SetRelated(object interface{}, relationId TypeId, targets interface{}) error
+}
+
+// Eager makes the query read the targets of the given lazy (`objectbox:"lazy"`) many-to-many relations together with
+// the results, like calling the generated Fetch*() methods afterwards. Find() reads the targets of all the results in
+// a single batch, i.e. each distinct target object is read only once and shared by all the results referencing it.
+// ForEach() does the same for chunks of (up to 100) results, passing them to the callback once a chunk is loaded.
+// Can be combined with NoEager().
+func (query *Query) Eager(relations ...*RelationToMany) *Query {
+	for _, relation := range relations {
+		if relation.Source.Id != query.entity.id {
+			query.eagerErr = fmt.Errorf("relation %d doesn't belong to the queried entity %s", relation.Id, query.entity.name)
+			return query
+		}
+	}
+	query.eager = append(query.eager, relations...)
+	return query
+}
+
+// NoEager makes the query skip reading the related objects which are normally loaded together with the results,
+// e.g. `objectbox:"link"` fields and non-lazy many-to-many relations. Useful when only the results' own data is needed.
+func (query *Query) NoEager() *Query {
+	query.noEager = true
+	return query
+}
+
+// readBox returns the box to read the query results with, honouring NoEager()
+func (query *Query) readBox() (*Box, error) {
+	if !query.noEager {
+		return query.box, nil
+	}
+
+	binding, ok := query.box.entity.binding.(ObjectBindingRelations)
+	if !ok {
+		if !query.box.entity.hasRelations {
+			return query.box, nil // there's nothing to skip
+		}
+		return nil, fmt.Errorf("Query.NoEager() can't be used on entity %s: its binding doesn't implement "+
+			"ObjectBindingRelations", query.entity.name)
+	}
+
+	// a copy of the entity, reading objects without their relations
+	var entity = *query.box.entity
+	entity.binding = noEagerBinding{entity.binding, binding}
+	return &Box{ObjectBox: query.box.ObjectBox, entity: &entity, cBox: query.box.cBox}, nil
+}
+
+// noEagerBinding loads objects using ObjectBindingRelations.LoadNoEager()
+type noEagerBinding struct {
+	ObjectBinding
+	relations ObjectBindingRelations
+}
+
+func (binding noEagerBinding) Load(ob *ObjectBox, bytes []byte) (interface{}, error) {
+	return binding.relations.LoadNoEager(ob, bytes)
+}
+
+// readWithEager calls the given read function and loads the Eager() relations of its results in a single read
+// transaction, so that the related objects are consistent with the results
+func (query *Query) readWithEager(read func() (interface{}, error)) (objects interface{}, err error) {
+	if len(query.eager) == 0 {
+		return read()
+	}
+
+	err = query.objectBox.RunInReadTx(func() error {
+		if objects, err = read(); err != nil {
+			return err
+		}
+		return query.loadEager(objects)
+	})
+	return objects, err
+}
+
+// loadEager reads the targets of the relations configured by Eager() for the given objects (a slice of results).
+// Must be called inside a read transaction, together with reading the objects themselves.
+func (query *Query) loadEager(objects interface{}) error {
+	if len(query.eager) == 0 {
+		return nil
+	}
+
+	// nil if not implemented, see loadEagerRelation()
+	binding, _ := query.box.entity.binding.(ObjectBindingRelations)
+
+	// collect the source objects as pointers, so that SetRelated() can update them in place
+	var slice = reflect.ValueOf(objects)
+	var sources = make([]interface{}, slice.Len())
+	var sourceIds = make([]uint64, slice.Len())
+	for i := range sources {
+		var value = slice.Index(i)
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Ptr && value.CanAddr() {
+			value = value.Addr()
+		}
+		sources[i] = value.Interface()
+
+		id, err := query.box.entity.binding.GetId(sources[i])
+		if err != nil {
+			return err
+		}
+		sourceIds[i] = id
+	}
+
+	for _, relation := range query.eager {
+		if err := query.loadEagerRelation(binding, relation, sources, sourceIds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (query *Query) loadEagerRelation(binding ObjectBindingRelations, relation *RelationToMany, sources []interface{}, sourceIds []uint64) error {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	targetBox, err := query.objectBox.box(relation.Target.Id)
+	if err != nil {
+		return err
+	}
+	var targetBinding = targetBox.entity.binding
+
+	var setRelated = func(source interface{}, related interface{}) error {
+		return binding.SetRelated(source, relation.Id, related)
+	}
+	if binding == nil {
+		index, err := relationFieldIndex(sources[0], relation, targetBinding.MakeSlice(0))
+		if err != nil {
+			return err
+		}
+		setRelated = func(source interface{}, related interface{}) error {
+			reflect.ValueOf(source).Elem().Field(index).Set(reflect.ValueOf(related))
+			return nil
+		}
+	}
+
+	// read the relation IDs of all sources first, then all the distinct targets at once
+	targetIds, err := query.box.RelationIdsMany(relation, sourceIds...)
+	if err != nil {
+		return err
+	}
+
+	var distinctIds []uint64
+	var seen = make(map[uint64]bool)
+	for _, sourceId := range sourceIds {
+		for _, id := range targetIds[sourceId] {
+			if !seen[id] {
+				seen[id] = true
+				distinctIds = append(distinctIds, id)
+			}
+		}
+	}
+
+	var targets = make(map[uint64]interface{}, len(distinctIds))
+	if len(distinctIds) > 0 {
+		objects, err := targetBox.GetManyExisting(distinctIds...)
+		if err != nil {
+			return err
+		}
+
+		var slice = reflect.ValueOf(objects)
+		for i := 0; i < slice.Len(); i++ {
+			var value = slice.Index(i)
+			if value.Kind() != reflect.Ptr {
+				value = value.Addr()
+			}
+			id, err := targetBinding.GetId(value.Interface())
+			if err != nil {
+				return err
+			}
+			targets[id] = value.Interface()
+		}
+	}
+
+	for i, source := range sources {
+		var related = targetBinding.MakeSlice(len(targetIds[sourceIds[i]]))
+		for _, id := range targetIds[sourceIds[i]] {
+			if target, found := targets[id]; found {
+				related = targetBinding.AppendToSlice(related, target)
+			}
+		}
+		if err := setRelated(source, related); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relationFieldIndex finds the field to assign the relation targets to for bindings not implementing
+// ObjectBindingRelations: the only exported field of the source struct having the type of the targets slice.
+func relationFieldIndex(source interface{}, relation *RelationToMany, targets interface{}) (int, error) {
+	var sourceType = reflect.TypeOf(source)
+	if sourceType.Kind() != reflect.Ptr || sourceType.Elem().Kind() != reflect.Struct {
+		return 0, fmt.Errorf("can't set relation %d on %T: expected a pointer to a struct", relation.Id, source)
+	}
+	sourceType = sourceType.Elem()
+
+	var targetsType = reflect.TypeOf(targets)
+	var index = -1
+	for i := 0; i < sourceType.NumField(); i++ {
+		var field = sourceType.Field(i)
+		if field.PkgPath != "" || field.Type != targetsType {
+			continue
+		} else if index >= 0 {
+			return 0, fmt.Errorf("can't set relation %d on %v: multiple fields have the type %v - implement "+
+				"ObjectBindingRelations to resolve it", relation.Id, sourceType, targetsType)
+		}
+		index = i
+	}
+
+	if index < 0 {
+		return 0, fmt.Errorf("can't set relation %d on %v: no field has the type %v", relation.Id, sourceType, targetsType)
+	}
+	return index, nil
+}
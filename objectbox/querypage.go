@@ -0,0 +1,406 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdlib.h>
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/google/flatbuffers/go"
+)
+
+// aliases of the conditions added to the internal queries used by FindPage():
+// "ID > last ID" and, for ordered queries, "order value > last value" and "order value == last value"
+const (
+	pageAlias           = "objectbox:page"
+	pageOrderAlias      = "objectbox:page-order"
+	pageOrderEqualAlias = "objectbox:page-order-equal"
+)
+
+// paramKey identifies a query condition whose parameters were changed by one of the Set*Params() methods
+type paramKey struct {
+	entityId   TypeId
+	propertyId TypeId
+	alias      string
+}
+
+// pageOrder describes the Order*() condition of a query used with FindPage()
+type pageOrder struct {
+	property      *PropertyInfo
+	descending    bool
+	caseSensitive bool
+}
+
+// rememberParams records a successful Set*Params() call so it can be replayed on the query used by FindPage()
+func (query *Query) rememberParams(identifier propertyOrAlias, err *error, setter func(*Query) error) {
+	if *err != nil {
+		return
+	}
+
+	var key = paramKey{entityId: identifier.entityId(), propertyId: identifier.propertyId()}
+	if alias := identifier.alias(); alias != nil {
+		key.alias = *alias
+	}
+
+	if query.params == nil {
+		query.params = make(map[paramKey]func(*Query) error)
+	}
+	query.params[key] = setter
+}
+
+// After sets the position for the next FindPage() call: the page starts right after the given object,
+// which is usually the last object of the previous page. For ordered queries, the object must be stored because its
+// order value is read from the database.
+func (query *Query) After(object interface{}) *Query {
+	query.afterId, query.afterErr = query.box.entity.binding.GetId(object)
+	query.afterValue = nil
+	return query
+}
+
+// AfterToken sets the position for the next FindPage() call to the one described by a token returned by FindPage().
+// An empty token resets the position to the first page.
+func (query *Query) AfterToken(token string) *Query {
+	query.afterId, query.afterValue, query.afterErr = 0, nil, nil
+	if len(token) == 0 {
+		return query
+	}
+
+	order, err := query.pageOrder()
+	if err != nil {
+		query.afterErr = err
+		return query
+	}
+
+	// "<ID>" for queries ordered by ID, "<ID>:<JSON order value>" otherwise
+	idText, valueText, hasValue := strings.Cut(token, ":")
+	id, err := strconv.ParseUint(idText, 10, 64)
+	if err != nil || id == 0 || hasValue != (order != nil) {
+		query.afterErr = fmt.Errorf("invalid page token %q", token)
+		return query
+	}
+
+	if order != nil {
+		value, err := order.property.parseJSON(json.RawMessage(valueText))
+		if err != nil || value == nil {
+			query.afterErr = fmt.Errorf("invalid page token %q", token)
+			return query
+		}
+		query.afterValue = value
+	}
+
+	query.afterId = id
+	return query
+}
+
+// FindPage returns at most limit objects matching the query, starting after the position set by After() or
+// AfterToken(). Instead of skipping all the preceding objects like Offset() does, the page continues right after the
+// last object of the previous page (keyset pagination), so the cost of reading a page doesn't grow with its position
+// in the results.
+//
+// The returned token describes the end of this page and can be passed to AfterToken() to read the next one;
+// it's empty if the page isn't full, i.e. there are no more results. The position is advanced automatically, so
+// calling FindPage() repeatedly on the same query reads all the pages one after another.
+//
+// Objects are returned in the order of their IDs, or, if the query has an Order*() condition, in that order with
+// objects having the same value ordered by their IDs. Only a single Order*() condition on a string, integer or
+// floating-point property is supported; the property must not have nil values and parameters of other conditions on
+// the order property must be set using an Alias(). Offset() and Limit() set on the query are ignored.
+func (query *Query) FindPage(limit uint64) (objects interface{}, nextToken string, err error) {
+	if err := query.check(); err != nil {
+		return nil, "", err
+	} else if query.afterErr != nil {
+		return nil, "", query.afterErr
+	} else if limit == 0 {
+		return nil, "", errors.New("page limit must be greater than zero")
+	}
+
+	order, err := query.pageOrder()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// an ordered query can't express "before the first value" so the first page is read by a query without the keyset
+	var firstPage = order != nil && query.afterId == 0
+
+	pageQuery, err := query.getPageQuery(order, firstPage)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, setter := range query.params {
+		if err := setter(pageQuery); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := pageQuery.Limit(limit).limitErr; err != nil {
+		return nil, "", err
+	}
+	pageQuery.eager, pageQuery.noEager = query.eager, query.noEager
+
+	var lastId uint64
+	var lastValue interface{}
+
+	// the order values must be read from the same snapshot as the page itself
+	err = query.objectBox.RunInReadTx(func() error {
+		if !firstPage {
+			if order != nil && query.afterValue == nil {
+				if query.afterValue, err = query.box.readPropertyValue(query.afterId, order.property); err != nil {
+					return err
+				}
+			}
+			if err := pageQuery.setPageParams(query.afterId, query.afterValue); err != nil {
+				return err
+			}
+		}
+
+		if objects, err = pageQuery.Find(); err != nil {
+			return err
+		}
+
+		if slice := reflect.ValueOf(objects); uint64(slice.Len()) == limit {
+			if lastId, err = query.box.entity.binding.GetId(slice.Index(slice.Len() - 1).Interface()); err != nil {
+				return err
+			}
+			if order != nil {
+				if lastValue, err = query.box.readPropertyValue(lastId, order.property); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	query.afterId, query.afterValue = lastId, lastValue
+	if lastId != 0 {
+		nextToken = strconv.FormatUint(lastId, 10)
+		if order != nil {
+			value, err := json.Marshal(lastValue)
+			if err != nil {
+				return nil, "", err
+			}
+			nextToken += ":" + string(value)
+		}
+	}
+
+	return objects, nextToken, nil
+}
+
+// pageOrder returns the order of this query as used by FindPage(); nil if the query is ordered by ID
+func (query *Query) pageOrder() (*pageOrder, error) {
+	if query.structure == nil || len(query.structure.orderFlags) == 0 {
+		return nil, nil
+	} else if len(query.structure.orderFlags) > 1 {
+		return nil, errors.New("FindPage() supports at most one Order*() condition")
+	}
+
+	var order = &pageOrder{}
+	for propertyId, flags := range query.structure.orderFlags {
+		for _, property := range query.entity.properties {
+			if property.Id == propertyId {
+				order.property = property
+			}
+		}
+		order.descending = flags&C.OBXOrderFlags_DESCENDING != 0
+		order.caseSensitive = flags&C.OBXOrderFlags_CASE_SENSITIVE != 0
+	}
+
+	if order.property == nil {
+		return nil, fmt.Errorf("order property not found on entity %s", query.entity.name)
+	}
+
+	switch order.property.Type {
+	case PropertyTypeByte, PropertyTypeChar, PropertyTypeShort, PropertyTypeInt, PropertyTypeLong,
+		PropertyTypeDate, PropertyTypeDateNano, PropertyTypeRelation,
+		PropertyTypeFloat, PropertyTypeDouble, PropertyTypeString:
+		return order, nil
+	default:
+		return nil, fmt.Errorf("FindPage() doesn't support ordering by property %s of type %d",
+			order.property.Name, order.property.Type)
+	}
+}
+
+// getPageQuery returns the internal query used by FindPage(), building it on the first call.
+// It has the same conditions as this query and is ordered by ID after the query's own order (if any). Unless it's
+// the query for the first page of an ordered query, it has an additional condition selecting objects after the
+// position given by setPageParams().
+func (query *Query) getPageQuery(order *pageOrder, firstPage bool) (*Query, error) {
+	var cached = &query.pageQuery
+	if firstPage {
+		cached = &query.firstPageQuery
+	}
+	if *cached != nil {
+		return *cached, nil
+	}
+
+	var idProperty = query.entity.idProperty()
+	if idProperty == nil {
+		return nil, fmt.Errorf("entity %s doesn't have an ID property", query.entity.name)
+	}
+
+	var id = PropertyUint64{&BaseProperty{Id: idProperty.Id, Entity: &Entity{Id: query.entity.id}}}
+	var conditions = make([]Condition, 0, len(query.conditions)+2)
+	conditions = append(conditions, query.conditions...)
+
+	if !firstPage {
+		var afterId = id.GreaterThan(0).Alias(pageAlias)
+		if order == nil {
+			conditions = append(conditions, afterId)
+		} else {
+			conditions = append(conditions, Any(
+				order.condition(query.entity.id, false).Alias(pageOrderAlias),
+				All(order.condition(query.entity.id, true).Alias(pageOrderEqualAlias), afterId),
+			))
+		}
+	}
+
+	// the ID order is applied after the query's own order, i.e. only decides between objects with the same value
+	conditions = append(conditions, id.OrderAsc())
+
+	pageQuery, err := query.box.QueryOrError(conditions...)
+	if err != nil {
+		return nil, err
+	}
+	*cached = pageQuery
+	return pageQuery, nil
+}
+
+// condition returns a condition comparing the order property with a value set later by setPageParams():
+// "equal to" or "after" (greater than or, for descending order, less than)
+func (order *pageOrder) condition(entityId TypeId, equal bool) Condition {
+	var base = &BaseProperty{Id: order.property.Id, Entity: &Entity{Id: entityId}}
+	switch order.property.Type {
+	case PropertyTypeString:
+		var property = PropertyString{base}
+		if equal {
+			return property.Equals("", order.caseSensitive)
+		} else if order.descending {
+			return property.LessThan("", order.caseSensitive)
+		}
+		return property.GreaterThan("", order.caseSensitive)
+	case PropertyTypeFloat, PropertyTypeDouble:
+		var property = PropertyFloat64{base}
+		if equal {
+			return property.Between(0, 0)
+		} else if order.descending {
+			return property.LessThan(0)
+		}
+		return property.GreaterThan(0)
+	default:
+		if order.unsigned() {
+			var property = PropertyUint64{base}
+			if equal {
+				return property.Equals(0)
+			} else if order.descending {
+				return property.LessThan(0)
+			}
+			return property.GreaterThan(0)
+		}
+
+		var property = PropertyInt64{base}
+		if equal {
+			return property.Equals(0)
+		} else if order.descending {
+			return property.LessThan(0)
+		}
+		return property.GreaterThan(0)
+	}
+}
+
+// unsigned returns whether the order property holds unsigned integers, see PropertyInfo.readFlatBuffers()
+func (order *pageOrder) unsigned() bool {
+	return order.property.Flags&(PropertyFlagUnsigned|PropertyFlagId) != 0 || order.property.Type == PropertyTypeRelation
+}
+
+// setPageParams sets the position of a page query built by getPageQuery(); orderValue is nil if ordered by ID
+func (query *Query) setPageParams(afterId uint64, orderValue interface{}) error {
+	if err := query.SetUint64Params(Alias(pageAlias), afterId); err != nil {
+		return err
+	}
+
+	if orderValue == nil {
+		return nil
+	}
+
+	var value = reflect.ValueOf(orderValue)
+	switch value.Kind() {
+	case reflect.String:
+		if err := query.SetStringParams(Alias(pageOrderAlias), value.String()); err != nil {
+			return err
+		}
+		return query.SetStringParams(Alias(pageOrderEqualAlias), value.String())
+	case reflect.Float32, reflect.Float64:
+		if err := query.SetFloat64Params(Alias(pageOrderAlias), value.Float()); err != nil {
+			return err
+		}
+		return query.SetFloat64Params(Alias(pageOrderEqualAlias), value.Float(), value.Float())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := query.SetInt64Params(Alias(pageOrderAlias), value.Int()); err != nil {
+			return err
+		}
+		return query.SetInt64Params(Alias(pageOrderEqualAlias), value.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// compared as unsigned by the database, values above math.MaxInt64 included; see SetUint64Params()
+		if err := query.SetUint64Params(Alias(pageOrderAlias), value.Uint()); err != nil {
+			return err
+		}
+		return query.SetUint64Params(Alias(pageOrderEqualAlias), value.Uint())
+	default:
+		return fmt.Errorf("unsupported page order value type %T", orderValue)
+	}
+}
+
+// readPropertyValue reads the value of the given property of a stored object; must be called inside a read transaction
+func (box *Box) readPropertyValue(id uint64, property *PropertyInfo) (interface{}, error) {
+	var data *C.void
+	var dataSize C.size_t
+	var dataPtr = unsafe.Pointer(data)
+
+	if err := cCall(func() C.obx_err {
+		return C.obx_box_get(box.cBox, C.obx_id(id), &dataPtr, &dataSize)
+	}); err != nil {
+		return nil, fmt.Errorf("can't read object %d: %v", id, err)
+	}
+
+	var bytes []byte
+	cVoidPtrToByteSlice(dataPtr, int(dataSize), &bytes)
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+
+	var value = property.readFlatBuffers(table)
+	if value == nil {
+		return nil, fmt.Errorf("object %d has a nil value of the order property %s, which is not supported by FindPage()",
+			id, property.Name)
+	}
+	return value, nil
+}
@@ -23,6 +23,7 @@ import (
 type conditionRelationOneToMany struct {
 	relation   *RelationToOne
 	conditions []Condition
+	backlink   bool    // explicit backlink, see Backlink()
 	alias      *string // this is only used to report an error
 }
 
@@ -31,6 +32,9 @@ func (condition *conditionRelationOneToMany) applyTo(qb *QueryBuilder, isRoot bo
 		return 0, fmt.Errorf("using Alias/As(\"%s\") on a OneToMany relation link is not supported", *condition.alias)
 	}
 
+	if condition.backlink {
+		return conditionIdFakeLink, qb.LinkBacklinkOneToMany(condition.relation, condition.conditions)
+	}
 	return conditionIdFakeLink, qb.LinkOneToMany(condition.relation, condition.conditions)
 }
 
@@ -73,6 +77,14 @@ func (relation *RelationToOne) Link(conditions ...Condition) Condition {
 	return &conditionRelationOneToMany{relation: relation, conditions: conditions}
 }
 
+// Backlink creates a connection in the reverse direction, from the relation target to the source entity, and takes
+// inner conditions to evaluate on the source entity. Use it in queries on the target entity, e.g. to find all targets
+// that are referenced by at least one source object matching the given conditions.
+// Unlike Link(), which recognizes backlinks automatically, this also works for relations from an entity to itself.
+func (relation *RelationToOne) Backlink(conditions ...Condition) Condition {
+	return &conditionRelationOneToMany{relation: relation, conditions: conditions, backlink: true}
+}
+
 // Equals finds entities with relation target ID equal to the given value
 func (relation RelationToOne) Equals(value uint64) Condition {
 	return &conditionClosure{
@@ -122,6 +134,7 @@ func (relation RelationToOne) NotIn(values ...uint64) Condition {
 type conditionRelationManyToMany struct {
 	relation   *RelationToMany
 	conditions []Condition
+	backlink   bool    // explicit backlink, see Backlink()
 	alias      *string // this is only used to report an error
 }
 
@@ -130,6 +143,9 @@ func (condition *conditionRelationManyToMany) applyTo(qb *QueryBuilder, isRoot b
 		return 0, fmt.Errorf("using Alias/As(\"%s\") on a ManyToMany relation link is not supported", *condition.alias)
 	}
 
+	if condition.backlink {
+		return conditionIdFakeLink, qb.LinkBacklinkManyToMany(condition.relation, condition.conditions)
+	}
 	return conditionIdFakeLink, qb.LinkManyToMany(condition.relation, condition.conditions)
 }
 
@@ -161,4 +177,10 @@ func (relation *RelationToMany) Link(conditions ...Condition) Condition {
 	return &conditionRelationManyToMany{relation: relation, conditions: conditions}
 }
 
+// Backlink creates a connection in the reverse direction, from the relation target to the source entity, and takes
+// inner conditions to evaluate on the source entity. See RelationToOne.Backlink() for details.
+func (relation *RelationToMany) Backlink(conditions ...Condition) Condition {
+	return &conditionRelationManyToMany{relation: relation, conditions: conditions, backlink: true}
+}
+
 // TODO contains() would make sense for many-to-many (slice)
@@ -0,0 +1,387 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include "objectbox.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RemovePolicy defines what happens to the source objects of a relation when its target object is removed,
+// similar to the ON DELETE clause of a foreign key in SQL databases.
+//
+// Policies are declared on the relation, e.g. using the struct tag `objectbox:"link onRemove:cascade"` on a to-one
+// relation field, and enforced by Box.Remove(), RemoveId(), RemoveIds(), RemoveAll() and Query.Remove() inside the
+// same write transaction as the removal itself, i.e. either all the changes are applied or none of them.
+//
+// AsyncBox.Remove() and RemoveId() are the exception: they're executed by the native async queue, which can't apply the
+// policies, so the removal proceeds as if all the policies were RemovePolicyNone. Use Box.Remove() for objects that
+// may be the target of a relation with a remove policy.
+type RemovePolicy int
+
+const (
+	// RemovePolicyNone leaves the source objects as they are, i.e. they keep referencing the removed object (default)
+	RemovePolicyNone RemovePolicy = iota
+
+	// RemovePolicyCascade removes the source objects as well, applying their own remove policies
+	RemovePolicyCascade
+
+	// RemovePolicyNullify clears the reference: sets the to-one relation property of the source objects to 0, or
+	// removes the standalone relation entries pointing to the removed object.
+	// To-one relations are cleared by rewriting the source objects using the dynamic binding, therefore all properties
+	// of the source entity must have types supported by DynamicBox; this is verified by Builder.Build().
+	RemovePolicyNullify
+
+	// RemovePolicyRestrict refuses to remove an object that is still referenced, failing with an error
+	RemovePolicyRestrict
+)
+
+func (policy RemovePolicy) String() string {
+	switch policy {
+	case RemovePolicyNone:
+		return "none"
+	case RemovePolicyCascade:
+		return "cascade"
+	case RemovePolicyNullify:
+		return "nullify"
+	case RemovePolicyRestrict:
+		return "restrict"
+	default:
+		return fmt.Sprintf("RemovePolicy(%d)", int(policy))
+	}
+}
+
+// RemovePolicy configures the remove policy of a to-one relation, overriding the one declared in the model.
+// Must be called after Model().
+func (builder *Builder) RemovePolicy(relation *RelationToOne, policy RemovePolicy) *Builder {
+	if builder.Error != nil {
+		return builder
+	} else if builder.model == nil {
+		builder.Error = errors.New("RemovePolicy() must be called after Model()")
+		return builder
+	}
+
+	if entity := builder.model.entitiesById[relation.Property.Entity.Id]; entity != nil {
+		for _, property := range entity.properties {
+			if property.Id == relation.Property.Id && len(property.RelationTarget) > 0 {
+				property.RemovePolicy = policy
+				return builder
+			}
+		}
+	}
+
+	builder.Error = fmt.Errorf("relation property %d of entity %d not found in the model",
+		relation.Property.Id, relation.Property.Entity.Id)
+	return builder
+}
+
+// RelationRemovePolicy configures the remove policy of a standalone (many-to-many) relation, overriding the one
+// declared in the model. Must be called after Model().
+func (builder *Builder) RelationRemovePolicy(relation *RelationToMany, policy RemovePolicy) *Builder {
+	if builder.Error != nil {
+		return builder
+	} else if builder.model == nil {
+		builder.Error = errors.New("RelationRemovePolicy() must be called after Model()")
+		return builder
+	}
+
+	if entity := builder.model.entitiesById[relation.Source.Id]; entity != nil {
+		for i := range entity.relations {
+			if entity.relations[i].Id == relation.Id {
+				entity.relations[i].RemovePolicy = policy
+				return builder
+			}
+		}
+	}
+
+	builder.Error = fmt.Errorf("relation %d of entity %d not found in the model", relation.Id, relation.Source.Id)
+	return builder
+}
+
+// incomingRelation is a relation pointing to an entity, with a remove policy to apply when its objects are removed
+type incomingRelation struct {
+	source     *entity
+	propertyId TypeId // to-one relation property; 0 for standalone relations
+	relationId TypeId // standalone relation; 0 for to-one relations
+	name       string
+	policy     RemovePolicy
+}
+
+// linkRemovePolicies collects the relations with a remove policy by their target entities
+func linkRemovePolicies(entitiesById map[TypeId]*entity, entitiesByName map[string]*entity) error {
+	// the model may be linked again if a previous BuildOrError() attempt failed
+	for _, target := range entitiesById {
+		target.incomingRelations = nil
+	}
+
+	for _, source := range entitiesById {
+		for _, property := range source.properties {
+			if property.RemovePolicy == RemovePolicyNone || len(property.RelationTarget) == 0 {
+				continue
+			}
+			if property.RemovePolicy == RemovePolicyNullify {
+				if err := source.checkNullifiable(); err != nil {
+					return fmt.Errorf("can't use remove policy %s on %s.%s: %v",
+						property.RemovePolicy, source.name, property.Name, err)
+				}
+			}
+			if target := entitiesByName[property.RelationTarget]; target != nil {
+				target.incomingRelations = append(target.incomingRelations, incomingRelation{
+					source:     source,
+					propertyId: property.Id,
+					name:       source.name + "." + property.Name,
+					policy:     property.RemovePolicy,
+				})
+			}
+		}
+
+		for _, relation := range source.relations {
+			if relation.RemovePolicy == RemovePolicyNone {
+				continue
+			}
+			if target := entitiesById[relation.TargetEntityId]; target != nil {
+				target.incomingRelations = append(target.incomingRelations, incomingRelation{
+					source:     source,
+					relationId: relation.Id,
+					name:       fmt.Sprintf("%s relation %d", source.name, relation.Id),
+					policy:     relation.RemovePolicy,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// checkNullifiable verifies the objects of the entity can be rewritten by nullifyProperty(), i.e. all its properties
+// are supported by the dynamic binding; otherwise the nullification would fail (or lose data) only once triggered.
+func (entity *entity) checkNullifiable() error {
+	for _, property := range entity.properties {
+		if !isDynamicPropertyType(property.Type) {
+			return fmt.Errorf("property %s has type %v, which isn't supported by the dynamic binding",
+				property.Name, property.Type)
+		}
+	}
+	return nil
+}
+
+// removalSet tracks objects being removed by a single call, preventing endless cascades on cyclic relations
+type removalSet map[TypeId]map[uint64]bool
+
+// add marks the given objects as being removed and returns those that haven't been marked before
+func (set removalSet) add(entityId TypeId, ids []uint64) []uint64 {
+	if set[entityId] == nil {
+		set[entityId] = make(map[uint64]bool)
+	}
+
+	var result = make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if !set[entityId][id] {
+			set[entityId][id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// removeWithPolicies applies the remove policies of the relations pointing to the given objects and then calls the
+// given remove function, all inside a single write transaction.
+func (box *Box) removeWithPolicies(ids func() ([]uint64, error), remove func() error) error {
+	return box.ObjectBox.RunInWriteTx(func() error {
+		targetIds, err := ids()
+		if err != nil {
+			return err
+		}
+
+		var removing = removalSet{}
+		if err := box.applyRemovePolicies(removing.add(box.entity.id, targetIds), removing); err != nil {
+			return err
+		}
+
+		// restrictions are checked only after all cascades have been collected so that references from objects removed
+		// by the same call (including self-relations) don't prevent the removal
+		if err := box.ObjectBox.checkRemoveRestrictions(removing); err != nil {
+			return err
+		}
+
+		// the given objects are removed by the remove function, the rest has been collected by cascades
+		for _, id := range targetIds {
+			delete(removing[box.entity.id], id)
+		}
+		for entityId, ids := range removing {
+			if err := box.ObjectBox.removeMany(entityId, ids); err != nil {
+				return err
+			}
+		}
+		return remove()
+	})
+}
+
+// applyRemovePolicies must be called inside a write transaction, before the given objects are removed.
+// Objects to cascade to are only collected in the removal set, not removed yet.
+func (box *Box) applyRemovePolicies(targetIds []uint64, removing removalSet) error {
+	for _, relation := range box.entity.incomingRelations {
+		if relation.policy != RemovePolicyCascade && relation.policy != RemovePolicyNullify {
+			continue
+		}
+
+		sourceBox, err := box.ObjectBox.box(relation.source.id)
+		if err != nil {
+			return err
+		}
+
+		for _, targetId := range targetIds {
+			sourceIds, err := box.backlinkIds(relation, targetId)
+			if err != nil {
+				return err
+			}
+
+			if relation.policy == RemovePolicyCascade {
+				err = sourceBox.applyRemovePolicies(removing.add(relation.source.id, sourceIds), removing)
+			} else if relation.relationId != 0 {
+				err = sourceBox.nullifyRelation(relation.relationId, sourceIds, targetId)
+			} else {
+				err = sourceBox.nullifyProperty(relation.propertyId, sourceIds)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRemoveRestrictions fails if any of the objects being removed is still referenced through a relation with the
+// restrict policy by an object that isn't being removed as well
+func (ob *ObjectBox) checkRemoveRestrictions(removing removalSet) error {
+	for entityId, ids := range removing {
+		box, err := ob.box(entityId)
+		if err != nil {
+			return err
+		}
+
+		for _, relation := range box.entity.incomingRelations {
+			if relation.policy != RemovePolicyRestrict {
+				continue
+			}
+			for targetId := range ids {
+				sourceIds, err := box.backlinkIds(relation, targetId)
+				if err != nil {
+					return err
+				}
+
+				var count = 0
+				for _, sourceId := range sourceIds {
+					if !removing[relation.source.id][sourceId] {
+						count++
+					}
+				}
+				if count > 0 {
+					return fmt.Errorf("can't remove %s object %d: it's referenced by %d object(s) through %s, "+
+						"which has the remove policy %s", box.entity.name, targetId, count, relation.name, relation.policy)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// backlinkIds returns IDs of the source objects of the given relation pointing to the target object
+func (box *Box) backlinkIds(relation incomingRelation, targetId uint64) ([]uint64, error) {
+	sourceBox, err := box.ObjectBox.box(relation.source.id)
+	if err != nil {
+		return nil, err
+	}
+
+	return cGetIds(func() *C.OBX_id_array {
+		if relation.relationId != 0 {
+			return C.obx_box_rel_get_backlink_ids(sourceBox.cBox, C.obx_schema_id(relation.relationId), C.obx_id(targetId))
+		}
+		return C.obx_box_get_backlink_ids(sourceBox.cBox, C.obx_schema_id(relation.propertyId), C.obx_id(targetId))
+	})
+}
+
+func (ob *ObjectBox) removeMany(entityId TypeId, ids map[uint64]bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	box, err := ob.box(entityId)
+	if err != nil {
+		return err
+	}
+
+	var idsSlice = make([]uint64, 0, len(ids))
+	for id := range ids {
+		idsSlice = append(idsSlice, id)
+	}
+
+	cIds, err := goIdsArrayToC(idsSlice)
+	if err != nil {
+		return err
+	}
+	defer cIds.free()
+
+	var cCount C.uint64_t
+	return cCall(func() C.obx_err {
+		return C.obx_box_remove_many(box.cBox, cIds.cArray, &cCount)
+	})
+}
+
+func (box *Box) nullifyRelation(relationId TypeId, sourceIds []uint64, targetId uint64) error {
+	for _, sourceId := range sourceIds {
+		if err := cCall(func() C.obx_err {
+			return C.obx_box_rel_remove(box.cBox, C.obx_schema_id(relationId), C.obx_id(sourceId), C.obx_id(targetId))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullifyProperty sets the relation property of the given objects to 0, rewriting them using the dynamic binding so
+// the rest of the object (including its relations) stays untouched.
+func (box *Box) nullifyProperty(propertyId TypeId, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	dynamicBox, err := box.ObjectBox.DynamicBox(box.entity.name)
+	if err != nil {
+		return err
+	}
+
+	var name = box.entity.propertyName(propertyId)
+	for _, id := range ids {
+		object, err := dynamicBox.Get(id)
+		if err != nil {
+			return err
+		} else if object == nil {
+			continue
+		}
+
+		object[name] = uint64(0)
+		if _, err := dynamicBox.Put(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
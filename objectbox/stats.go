@@ -0,0 +1,157 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// the database file inside the directory
+const dataFileName = "data.mdb"
+
+// default of obx_opt_max_db_size_in_kb(): 1 GB
+const defaultMaxSizeInKb = 1024 * 1024
+
+// Stats is a snapshot of the store state and of the activity since the store was opened, see ObjectBox.Stats().
+//
+// Transaction and async counters are tracked by the Go binding, i.e. they don't include activity of the native
+// library itself (e.g. the sync client applying changes). The native library currently doesn't expose the used size
+// of the database file, the number of active readers nor the async queue length.
+//
+// The transaction counters only include explicit transactions: those started by RunInReadTx(), RunInWriteTx(),
+// BeginRead() and BeginWrite(), as well as the ones the Go binding starts itself to group multiple native calls
+// (e.g. by Box.PutMany() or Query.Find() with related objects loading). A single Box or Query call outside such
+// a transaction runs in an implicit transaction of the native library, which isn't counted.
+type Stats struct {
+	// FileSize is the current size of the database file in bytes
+	FileSize int64
+
+	// MaxSize is the maximum size the database file can grow to in bytes, see Builder.MaxSizeInKb()
+	MaxSize uint64
+
+	// EntityCounts holds the number of stored objects, by entity name
+	EntityCounts map[string]uint64
+
+	// ActiveExplicitReadTransactions is the number of currently open explicit read transactions
+	ActiveExplicitReadTransactions int64
+
+	// ActiveExplicitWriteTransactions is the number of currently open explicit write transactions (at most one at a time)
+	ActiveExplicitWriteTransactions int64
+
+	// ExplicitReadTransactions is the number of finished explicit read transactions
+	ExplicitReadTransactions uint64
+
+	// ExplicitWriteTransactionsCommitted is the number of successfully committed explicit write transactions
+	ExplicitWriteTransactionsCommitted uint64
+
+	// ExplicitWriteTransactionsAborted is the number of explicit write transactions which were rolled back
+	ExplicitWriteTransactionsAborted uint64
+
+	// AsyncSubmitted is the number of operations successfully submitted to the async queue
+	AsyncSubmitted uint64
+
+	// AsyncRejected is the number of async operations which couldn't be submitted (e.g. the queue was full)
+	AsyncRejected uint64
+}
+
+// statsCounters are updated atomically; only 64-bit fields to keep them aligned on 32-bit platforms as well
+type statsCounters struct {
+	activeRead     int64
+	activeWrite    int64
+	read           uint64
+	writeCommitted uint64
+	writeAborted   uint64
+	asyncSubmitted uint64
+	asyncRejected  uint64
+}
+
+// Stats collects the current statistics, see Stats for the details.
+// Counting the objects executes a (short) read transaction which is not included in the returned counters.
+func (ob *ObjectBox) Stats() (*Stats, error) {
+	if ob.store == nil {
+		return nil, errors.New("store has already been closed")
+	}
+
+	var stats = &Stats{
+		MaxSize:                            ob.maxSizeInKb * 1024,
+		EntityCounts:                       make(map[string]uint64, len(ob.entitiesById)),
+		ActiveExplicitReadTransactions:     atomic.LoadInt64(&ob.stats.activeRead),
+		ActiveExplicitWriteTransactions:    atomic.LoadInt64(&ob.stats.activeWrite),
+		ExplicitReadTransactions:           atomic.LoadUint64(&ob.stats.read),
+		ExplicitWriteTransactionsCommitted: atomic.LoadUint64(&ob.stats.writeCommitted),
+		ExplicitWriteTransactionsAborted:   atomic.LoadUint64(&ob.stats.writeAborted),
+		AsyncSubmitted:                     atomic.LoadUint64(&ob.stats.asyncSubmitted),
+		AsyncRejected:                      atomic.LoadUint64(&ob.stats.asyncRejected),
+	}
+
+	info, err := os.Stat(filepath.Join(ob.directory, dataFileName))
+	if err != nil {
+		return nil, err
+	}
+	stats.FileSize = info.Size()
+
+	err = ob.RunInReadTx(func() error {
+		for id, entity := range ob.entitiesById {
+			box, err := ob.box(id)
+			if err != nil {
+				return err
+			}
+			if stats.EntityCounts[entity.name], err = box.Count(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (ob *ObjectBox) txStarted(readOnly bool) {
+	if readOnly {
+		atomic.AddInt64(&ob.stats.activeRead, 1)
+	} else {
+		atomic.AddInt64(&ob.stats.activeWrite, 1)
+	}
+}
+
+func (ob *ObjectBox) txFinished(readOnly bool, committed bool) {
+	if readOnly {
+		atomic.AddInt64(&ob.stats.activeRead, -1)
+		atomic.AddUint64(&ob.stats.read, 1)
+	} else {
+		atomic.AddInt64(&ob.stats.activeWrite, -1)
+		if committed {
+			atomic.AddUint64(&ob.stats.writeCommitted, 1)
+		} else {
+			atomic.AddUint64(&ob.stats.writeAborted, 1)
+		}
+	}
+}
+
+func (ob *ObjectBox) asyncSubmitted(err error) {
+	if err == nil {
+		atomic.AddUint64(&ob.stats.asyncSubmitted, 1)
+	} else {
+		atomic.AddUint64(&ob.stats.asyncRejected, 1)
+	}
+}
@@ -23,6 +23,7 @@ package objectbox
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -229,6 +230,35 @@ func (client *SyncClient) WaitForLogin(timeout time.Duration) (successful bool,
 	}
 }
 
+// WaitForLoginCtx is like WaitForLogin() but waits until the given context is cancelled or its deadline exceeded,
+// instead of a fixed timeout. Returns:
+// 		(true, nil) in case the login was successful;
+// 		(false, ctx.Err()) if the context was done before a successful login;
+// 		(false, error) if an error occurred (such as wrong credentials)
+func (client *SyncClient) WaitForLoginCtx(ctx context.Context) (successful bool, err error) {
+	// the native wait can't be interrupted so it's done in short slices, checking the context in between
+	const interval = 100 * time.Millisecond
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		var timeout = interval
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); remaining < timeout {
+				timeout = remaining
+			}
+		}
+		if timeout < time.Millisecond {
+			timeout = time.Millisecond
+		}
+
+		if successful, err = client.WaitForLogin(timeout); successful || err != nil {
+			return successful, err
+		}
+	}
+}
+
 // RequestUpdates can be used to manually synchronize incoming changes in case the client is running in "Manual" or
 // "AutoNoPushes" mode (i.e. it doesn't get the updates automatically). Additionally, it can be used to subscribe for
 // future pushes (similar to the "Auto" mode).
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ToOne is a lazily loaded to-one relation to an object of type T, an alternative to `objectbox:"link"` fields, which
+// are read together with the source object. Only the target ID is stored with the source object; the target is read
+// on the first Get() call and cached afterwards, so reading large object graphs doesn't deserialize all the targets.
+//
+// A ToOne read from the database is attached to the store by the entity binding using NewToOne(). For new objects,
+// the target assigned using Set() is inserted (if new) and its ID stored when the source object is put, see PutTarget().
+// Assigning TargetId directly discards a target previously assigned by Set().
+//
+// Note: bindings generated by objectbox-generator don't support ToOne fields yet, i.e. they don't call NewToOne() and
+// PutTarget(); use it with hand-written bindings meanwhile.
+type ToOne[T any] struct {
+	// TargetId is the ID of the related object or 0 if there's none
+	TargetId uint64
+
+	objectBox      *ObjectBox
+	targetEntityId TypeId
+
+	target   *T     // cached target object
+	targetOf uint64 // TargetId the cached target has been read (or assigned by Set()) for
+	changed  bool   // target has been assigned by Set() and not yet stored by PutTarget()
+}
+
+// NewToOne creates a relation attached to the given store, targeting the object with the given ID.
+// This is called by generated entity bindings when reading an object; the target object is not read until Get().
+func NewToOne[T any](ob *ObjectBox, targetEntityId TypeId, targetId uint64) ToOne[T] {
+	return ToOne[T]{TargetId: targetId, objectBox: ob, targetEntityId: targetEntityId}
+}
+
+// Get returns the related object, reading it from the database on the first call.
+// Returns nil (and no error) if there's no related object.
+func (rel *ToOne[T]) Get() (*T, error) {
+	rel.discardStale()
+	if rel.changed || (rel.target != nil && rel.targetOf == rel.TargetId) {
+		return rel.target, nil
+	}
+
+	if rel.TargetId == 0 {
+		return nil, nil
+	}
+
+	if rel.objectBox == nil {
+		return nil, errors.New("can't read the related object: the relation is not attached to a store (see NewToOne)")
+	}
+
+	box, err := rel.objectBox.box(rel.targetEntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := box.Get(rel.TargetId)
+	if err != nil {
+		return nil, err
+	} else if object == nil {
+		return nil, nil
+	}
+
+	target, ok := object.(*T)
+	if !ok {
+		return nil, fmt.Errorf("entity %d binding returned %T instead of %T", rel.targetEntityId, object, target)
+	}
+
+	rel.target = target
+	rel.targetOf = rel.TargetId
+	return target, nil
+}
+
+// Set assigns the related object; pass nil to remove the relation.
+// The change is stored when the source object is put, which also inserts the target if it's new.
+func (rel *ToOne[T]) Set(target *T) {
+	rel.target = target
+	rel.changed = true
+	if target == nil {
+		rel.TargetId = 0
+	}
+	rel.targetOf = rel.TargetId
+}
+
+// IsLoaded returns true if the related object has been read (or assigned) and Get() won't access the database.
+func (rel *ToOne[T]) IsLoaded() bool {
+	rel.discardStale()
+	return rel.changed || rel.TargetId == 0 || (rel.target != nil && rel.targetOf == rel.TargetId)
+}
+
+// discardStale forgets the target assigned by Set() if TargetId has been assigned directly since then
+func (rel *ToOne[T]) discardStale() {
+	if rel.changed && rel.targetOf != rel.TargetId {
+		rel.changed = false
+		rel.target = nil
+	}
+}
+
+// PutTarget stores the target assigned by Set(), inserting it if it's new, and updates TargetId.
+// This is called by generated entity bindings before the source object is put; it attaches the relation to the store.
+func (rel *ToOne[T]) PutTarget(ob *ObjectBox, targetEntityId TypeId) error {
+	rel.objectBox = ob
+	rel.targetEntityId = targetEntityId
+
+	rel.discardStale()
+	if !rel.changed || rel.target == nil {
+		rel.changed = false
+		return nil
+	}
+
+	box, err := ob.box(targetEntityId)
+	if err != nil {
+		return err
+	}
+
+	id, err := box.entity.binding.GetId(rel.target)
+	if err != nil {
+		return err
+	}
+
+	if id == 0 {
+		if id, err = box.Put(rel.target); err != nil {
+			return err
+		}
+	}
+
+	rel.TargetId = id
+	rel.targetOf = id
+	rel.changed = false
+	return nil
+}
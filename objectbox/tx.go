@@ -0,0 +1,198 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include "objectbox.h"
+
+#ifdef _WIN32
+#include <windows.h>
+static uint64_t currentThreadId() { return (uint64_t) GetCurrentThreadId(); }
+#else
+#include <pthread.h>
+static uint64_t currentThreadId() { return (uint64_t) (uintptr_t) pthread_self(); }
+#endif
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Tx is an explicit transaction handle, as an alternative to the closure-based RunInReadTx() and RunInWriteTx().
+// Use ObjectBox.BeginRead() or ObjectBox.BeginWrite() to create one and always finish it using Commit() or Abort(),
+// for example:
+// 		tx, err := ob.BeginWrite()
+// 		if err != nil {
+// 			return err
+// 		}
+// 		defer tx.Abort() // no-op if already committed
+// 		box, err := tx.Box(model.EntityBinding.Id)
+// 		...
+// 		return tx.Commit()
+//
+// Same as with the closure-based API, the current goroutine is locked to its OS thread while the transaction is open.
+// Therefore, the transaction (and all operations which should be part of it) must be used from the goroutine which
+// created it; using it from another goroutine results in an error.
+type Tx struct {
+	objectBox *ObjectBox
+	cTxn      *C.OBX_txn
+	readOnly  bool
+	threadId  C.uint64_t
+	started   time.Time
+	mutex     sync.Mutex
+}
+
+// BeginRead starts a read transaction. Multiple read transaction may be executed concurrently.
+func (ob *ObjectBox) BeginRead() (*Tx, error) {
+	return ob.beginTx(true)
+}
+
+// BeginWrite starts a write transaction. Only one write transaction may be active at a time (concurrently),
+// i.e. this blocks until other write transactions are finished.
+func (ob *ObjectBox) BeginWrite() (*Tx, error) {
+	return ob.beginTx(false)
+}
+
+func (ob *ObjectBox) beginTx(readOnly bool) (*Tx, error) {
+	// the thread is kept locked until the transaction is closed, see Tx.close()
+	runtime.LockOSThread()
+
+	var tx = &Tx{
+		objectBox: ob,
+		readOnly:  readOnly,
+		threadId:  C.currentThreadId(),
+		started:   time.Now(),
+	}
+
+	if readOnly {
+		tx.cTxn = C.obx_txn_read(ob.store)
+	} else {
+		tx.cTxn = C.obx_txn_write(ob.store)
+	}
+
+	if tx.cTxn == nil {
+		var err = createError()
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	ob.txStarted(readOnly)
+
+	return tx, nil
+}
+
+// IsReadOnly returns true for transactions created by BeginRead()
+func (tx *Tx) IsReadOnly() bool {
+	return tx.readOnly
+}
+
+// Box returns an Entity Box for the given entity ID, bound to this transaction: all operations executed on the Box
+// become part of this transaction. Same as the transaction itself, the Box can only be used from the goroutine which
+// created the transaction and only until the transaction is finished; otherwise its methods (and those of the queries
+// created from it) return an error. Async operations (Box.Async()) are executed outside of the transaction.
+func (tx *Tx) Box(entityId TypeId) (*Box, error) {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if err := tx.check(); err != nil {
+		return nil, err
+	}
+
+	if tx.objectBox.entitiesById[entityId] == nil {
+		return nil, fmt.Errorf("no entity registered for entity ID %d", entityId)
+	}
+
+	box, err := tx.objectBox.box(entityId)
+	if err != nil {
+		return nil, err
+	}
+
+	// a copy sharing the native box, see Box.checkTx()
+	var bound = *box
+	bound.tx = tx
+	return &bound, nil
+}
+
+// Commit finishes the transaction: the changes done in a write transaction are persisted. For a read transaction,
+// this only releases the resources held by it (same as Abort()).
+// The transaction can't be used anymore afterwards, regardless of the result.
+func (tx *Tx) Commit() error {
+	if tx.readOnly {
+		return tx.close(false)
+	}
+	return tx.close(true)
+}
+
+// Abort finishes the transaction, rolling back all changes done in a write transaction.
+// The transaction can't be used anymore afterwards. Calling Abort() on a finished transaction is a no-op so it can
+// be deferred right after the transaction is created.
+func (tx *Tx) Abort() error {
+	tx.mutex.Lock()
+	var closed = tx.cTxn == nil
+	tx.mutex.Unlock()
+
+	if closed {
+		return nil
+	}
+	return tx.close(false)
+}
+
+func (tx *Tx) close(commit bool) error {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if err := tx.check(); err != nil {
+		return err
+	}
+
+	var cTxn = tx.cTxn
+	tx.cTxn = nil
+
+	var err error
+	if commit {
+		err = cCall(func() C.obx_err { return C.obx_txn_success(cTxn) })
+	} else {
+		err = cCall(func() C.obx_err { return C.obx_txn_close(cTxn) })
+	}
+
+	// the matching call to runtime.LockOSThread() in beginTx()
+	runtime.UnlockOSThread()
+	tx.objectBox.txFinished(tx.readOnly, commit && err == nil)
+	tx.objectBox.logTx(tx.readOnly, commit && err == nil, tx.started, err)
+	return err
+}
+
+// check verifies the transaction is still open and used from the right goroutine; the mutex must be held
+func (tx *Tx) check() error {
+	if tx.cTxn == nil {
+		return errors.New("transaction has already been closed")
+	}
+
+	// the creating goroutine is locked to the thread, thus a different thread means a different goroutine
+	if C.currentThreadId() != tx.threadId {
+		return errors.New("transaction can only be used from the goroutine which created it")
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox
+
+import (
+	"fmt"
+)
+
+// TypedBox provides type-safe CRUD access to objects of type T, as an alternative to the generated per-entity Box
+// wrappers. It works with any ObjectBinding which loads objects as *T, e.g. the generated bindings:
+// 		var box = objectbox.NewTypedBox[model.Task](ob, model.TaskBinding.Id)
+// 		id, err := box.Put(&model.Task{Text: "Buy milk"})
+// 		task, err := box.Get(id)
+//
+// Methods not overridden here (e.g. Count(), RemoveAll()) are provided by the embedded *Box.
+type TypedBox[T any] struct {
+	*Box
+}
+
+// NewTypedBox returns a TypedBox for the given entity ID or panics on error (in case entity with the given ID doesn't
+// exist or its binding doesn't work with objects of type T). Use NewTypedBoxOrError for an explicit error check.
+func NewTypedBox[T any](ob *ObjectBox, entityId TypeId) *TypedBox[T] {
+	box, err := NewTypedBoxOrError[T](ob, entityId)
+	if err != nil {
+		panic(fmt.Sprintf("Could not create typed box for entity ID %d: %s", entityId, err))
+	}
+	return box
+}
+
+// NewTypedBoxOrError returns a TypedBox for the given entity ID.
+func NewTypedBoxOrError[T any](ob *ObjectBox, entityId TypeId) (*TypedBox[T], error) {
+	if ob.entitiesById[entityId] == nil {
+		return nil, fmt.Errorf("no entity registered for entity ID %d", entityId)
+	}
+
+	box, err := ob.box(entityId)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the binding type matches T, see typedSlice()
+	switch slice := box.entity.binding.MakeSlice(0).(type) {
+	case []*T, []T:
+	default:
+		var t T
+		return nil, fmt.Errorf("entity ID %d binding works with %T which is incompatible with %T", entityId, slice, t)
+	}
+
+	return &TypedBox[T]{Box: box}, nil
+}
+
+// Put synchronously inserts/updates a single object.
+// In case the Id is not specified, it would be assigned automatically (auto-increment).
+// When inserting, the Id property on the passed object will be assigned the new ID as well.
+func (box *TypedBox[T]) Put(object *T) (uint64, error) {
+	return box.Box.Put(object)
+}
+
+// Insert synchronously inserts a single object. As opposed to Put, Insert will fail if given an ID that already exists.
+func (box *TypedBox[T]) Insert(object *T) (uint64, error) {
+	return box.Box.Insert(object)
+}
+
+// Update synchronously updates a single object.
+// As opposed to Put, Update will fail if an object with the same ID is not found in the database.
+func (box *TypedBox[T]) Update(object *T) error {
+	return box.Box.Update(object)
+}
+
+// PutMany inserts multiple objects in single transaction.
+// In case Ids are not set on the objects, they would be assigned automatically (auto-increment).
+//
+// Returns: IDs of the put objects (in the same order).
+func (box *TypedBox[T]) PutMany(objects []*T) ([]uint64, error) {
+	return box.Box.PutMany(objects)
+}
+
+// Get reads a single object.
+//
+// Returns nil (and no error) in case the object with the given ID doesn't exist.
+func (box *TypedBox[T]) Get(id uint64) (*T, error) {
+	object, err := box.Box.Get(id)
+	if err != nil {
+		return nil, err
+	} else if object == nil {
+		return nil, nil
+	}
+	return object.(*T), nil
+}
+
+// GetMany reads multiple objects at once.
+// If any of the objects doesn't exist, its position in the return slice is nil, regardless of the binding type.
+func (box *TypedBox[T]) GetMany(ids ...uint64) ([]*T, error) {
+	objects, err := box.Box.GetMany(ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = typedSlice[T](objects)
+
+	// by-value bindings return a zero value for missing objects; there's no valid object with ID 0
+	if _, byValue := objects.([]T); byValue {
+		for i, object := range result {
+			if id, err := box.entity.binding.GetId(object); err != nil {
+				return nil, err
+			} else if id == 0 {
+				result[i] = nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// GetManyExisting reads multiple objects at once, skipping those that do not exist.
+func (box *TypedBox[T]) GetManyExisting(ids ...uint64) ([]*T, error) {
+	objects, err := box.Box.GetManyExisting(ids...)
+	if err != nil {
+		return nil, err
+	}
+	return typedSlice[T](objects), nil
+}
+
+// GetAll reads all stored objects
+func (box *TypedBox[T]) GetAll() ([]*T, error) {
+	objects, err := box.Box.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return typedSlice[T](objects), nil
+}
+
+// ForEach streams all stored objects to the given callback, one by one. See Box.ForEach() for details.
+func (box *TypedBox[T]) ForEach(fn func(object *T) (bool, error)) error {
+	return box.Box.ForEach(func(object interface{}) (bool, error) {
+		return fn(object.(*T))
+	})
+}
+
+// Backlinks reads all objects of this box pointing to the given target object ID using the relation property,
+// i.e. the children of a parent object in a one-to-many relation. See Box.BacklinkIds().
+func (box *TypedBox[T]) Backlinks(relation *RelationToOne, targetId uint64) ([]*T, error) {
+	ids, err := box.Box.BacklinkIds(relation, targetId)
+	if err != nil {
+		return nil, err
+	}
+	return box.GetManyExisting(ids...)
+}
+
+// RelationBacklinks reads all objects of this box related to the given target object ID using the standalone
+// relation, i.e. navigates a many-to-many relation in the reverse direction. See Box.RelationBacklinkIds().
+func (box *TypedBox[T]) RelationBacklinks(relation *RelationToMany, targetId uint64) ([]*T, error) {
+	ids, err := box.Box.RelationBacklinkIds(relation, targetId)
+	if err != nil {
+		return nil, err
+	}
+	return box.GetManyExisting(ids...)
+}
+
+// Remove deletes a single object
+func (box *TypedBox[T]) Remove(object *T) error {
+	return box.Box.Remove(object)
+}
+
+// Query creates a query with the given conditions.
+// Note: this function panics if you try to create illegal queries; e.g. use properties of an alien type.
+// This is typically a programming error. Use QueryOrError instead if you want the explicit error check.
+func (box *TypedBox[T]) Query(conditions ...Condition) *TypedQuery[T] {
+	return &TypedQuery[T]{box.Box.Query(conditions...)}
+}
+
+// QueryOrError creates a query with the given conditions.
+func (box *TypedBox[T]) QueryOrError(conditions ...Condition) (*TypedQuery[T], error) {
+	query, err := box.Box.QueryOrError(conditions...)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedQuery[T]{query}, nil
+}
+
+// TypedQuery provides a type-safe way to search stored objects of type T, see TypedBox.Query().
+type TypedQuery[T any] struct {
+	*Query
+}
+
+// Find returns all objects matching the query
+func (query *TypedQuery[T]) Find() ([]*T, error) {
+	objects, err := query.Query.Find()
+	if err != nil {
+		return nil, err
+	}
+	return typedSlice[T](objects), nil
+}
+
+// ForEach streams all objects matching the query to the given callback, one by one. See Query.ForEach() for details.
+func (query *TypedQuery[T]) ForEach(fn func(object *T) (bool, error)) error {
+	return query.Query.ForEach(func(object interface{}) (bool, error) {
+		return fn(object.(*T))
+	})
+}
+
+// Offset defines the index of the first object to process (how many objects to skip)
+func (query *TypedQuery[T]) Offset(offset uint64) *TypedQuery[T] {
+	query.Query.Offset(offset)
+	return query
+}
+
+// Limit sets the number of elements to process by the query
+func (query *TypedQuery[T]) Limit(limit uint64) *TypedQuery[T] {
+	query.Query.Limit(limit)
+	return query
+}
+
+// Eager makes the query read the targets of the given lazy relations together with the results, see Query.Eager()
+func (query *TypedQuery[T]) Eager(relations ...*RelationToMany) *TypedQuery[T] {
+	query.Query.Eager(relations...)
+	return query
+}
+
+// NoEager makes the query skip reading related objects, see Query.NoEager()
+func (query *TypedQuery[T]) NoEager() *TypedQuery[T] {
+	query.Query.NoEager()
+	return query
+}
+
+// After sets the position for the next FindPage() call, see Query.After()
+func (query *TypedQuery[T]) After(object *T) *TypedQuery[T] {
+	query.Query.After(object)
+	return query
+}
+
+// AfterToken sets the position for the next FindPage() call, see Query.AfterToken()
+func (query *TypedQuery[T]) AfterToken(token string) *TypedQuery[T] {
+	query.Query.AfterToken(token)
+	return query
+}
+
+// FindPage returns at most limit objects following the current position, see Query.FindPage()
+func (query *TypedQuery[T]) FindPage(limit uint64) ([]*T, string, error) {
+	objects, nextToken, err := query.Query.FindPage(limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return typedSlice[T](objects), nextToken, nil
+}
+
+// typedSlice converts a slice created by ObjectBinding.MakeSlice() to []*T.
+// Bindings of "by-value" entities create []T slices, whose items are converted to pointers.
+func typedSlice[T any](slice interface{}) []*T {
+	if values, ok := slice.([]T); ok {
+		var pointers = make([]*T, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		return pointers
+	}
+	return slice.([]*T)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestBackupRestore(t *testing.T) {
+	env := model.NewTestEnv(t).SetOptions(model.TestEnvOptions{PopulateRelations: true})
+	defer env.Close()
+
+	env.Populate(10)
+
+	// create a gap in the ID sequence
+	assert.NoErr(t, env.Box.RemoveId(5))
+
+	var backupFile = filepath.Join(env.Directory, "backup.obx")
+	assert.NoErr(t, env.ObjectBox.BackupToFile(backupFile))
+
+	restored, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		RestoreFromFile(backupFile).BuildOrError()
+	assert.NoErr(t, err)
+	defer restored.Close()
+
+	expected, err := env.Box.GetAll()
+	assert.NoErr(t, err)
+	actual, err := model.BoxForEntity(restored).GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 9, len(actual))
+	assert.Eq(t, expected, actual)
+
+	expectedRelated, err := model.BoxForTestEntityRelated(env.ObjectBox).GetAll()
+	assert.NoErr(t, err)
+	actualRelated, err := model.BoxForTestEntityRelated(restored).GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, expectedRelated, actualRelated)
+
+	// lazy-loaded standalone relation
+	assert.NoErr(t, env.Box.FetchRelatedPtrSlice(expected...))
+	assert.NoErr(t, model.BoxForEntity(restored).FetchRelatedPtrSlice(actual...))
+	assert.Eq(t, expected, actual)
+
+	relatedIds, err := model.BoxForEntity(restored).RelationIds(model.Entity_.RelatedPtrSlice, actual[0].Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(relatedIds))
+
+	// new objects get IDs following the restored ones
+	id, err := model.BoxForEntity(restored).Put(&model.Entity{})
+	assert.NoErr(t, err)
+	assert.True(t, id > actual[len(actual)-1].Id)
+}
+
+func TestBackupRestoreInvalid(t *testing.T) {
+	var dir = t.TempDir()
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+
+	_, err = model.BoxForEntity(ob).Put(model.Entity47())
+	assert.NoErr(t, err)
+
+	var backupFile = filepath.Join(t.TempDir(), "backup.obx")
+	assert.NoErr(t, ob.BackupToFile(backupFile))
+	ob.Close()
+
+	// can't restore into a non-empty database
+	_, err = objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		RestoreFromFile(backupFile).BuildOrError()
+	assert.Err(t, err)
+
+	// invalid file
+	assert.NoErr(t, ioutil.WriteFile(backupFile, []byte("invalid"), 0644))
+	_, err = objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		RestoreFromFile(backupFile).BuildOrError()
+	assert.Err(t, err)
+
+	// object size larger than the rest of the file
+	var data bytes.Buffer
+	data.WriteString("OBXGOBAK")
+	assert.NoErr(t, binary.Write(&data, binary.LittleEndian, []uint32{2, 0}))
+	data.WriteByte('E')
+	assert.NoErr(t, binary.Write(&data, binary.LittleEndian, uint32(model.EntityBinding.Id)))
+	assert.NoErr(t, binary.Write(&data, binary.LittleEndian, uint64(1)))
+	assert.NoErr(t, binary.Write(&data, binary.LittleEndian, uint32(0xFFFFFFFF)))
+	data.WriteString("truncated")
+	assert.NoErr(t, ioutil.WriteFile(backupFile, data.Bytes(), 0644))
+	_, err = objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		RestoreFromFile(backupFile).BuildOrError()
+	assert.Err(t, err)
+}
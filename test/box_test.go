@@ -17,6 +17,7 @@
 package objectbox_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/objectbox/objectbox-go/test/assert"
@@ -257,6 +258,37 @@ func TestBoxCount(t *testing.T) {
 	assert.Eq(t, c/2, count)
 }
 
+func TestBoxForEach(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	var ids []uint64
+	assert.NoErr(t, env.Box.ForEach(func(object interface{}) (bool, error) {
+		ids = append(ids, object.(*model.Entity).Id)
+		return true, nil
+	}))
+	assert.Eq(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ids)
+
+	// stop early
+	var visited = 0
+	assert.NoErr(t, env.Box.ForEach(func(object interface{}) (bool, error) {
+		visited++
+		return visited < 3, nil
+	}))
+	assert.Eq(t, 3, visited)
+
+	// error is passed through
+	visited = 0
+	var err = env.Box.ForEach(func(object interface{}) (bool, error) {
+		visited++
+		return true, errors.New("stop")
+	})
+	assert.Eq(t, errors.New("stop"), err)
+	assert.Eq(t, 1, visited)
+}
+
 func TestBoxEmpty(t *testing.T) {
 	var env = model.NewTestEnv(t)
 	defer env.Close()
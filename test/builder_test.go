@@ -0,0 +1,111 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestBuilderTempDirectory(t *testing.T) {
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+
+	var dir = ob.Directory()
+	_, err = os.Stat(dir)
+	assert.NoErr(t, err)
+
+	ob.Close()
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = objectbox.NewBuilder().TempDirectory().Directory(t.TempDir()).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.Err(t, err)
+}
+
+func TestBuilderReadOnly(t *testing.T) {
+	var dir = t.TempDir()
+
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	assert.Eq(t, dir, ob.Directory())
+	_, err = model.BoxForEntity(ob).Put(model.Entity47())
+	assert.NoErr(t, err)
+	ob.Close()
+
+	ob, err = objectbox.NewBuilder().Directory(dir).ReadOnly().Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	_, err = box.Put(model.Entity47())
+	assert.Err(t, err)
+}
+
+func TestBuilderOptions(t *testing.T) {
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		FileMode(0600).
+		ValidateOnOpen(10, true).
+		AsyncTimeout(500).
+		AsyncMaxQueueLength(1000).
+		AsyncThrottle(500, time.Millisecond).
+		AsyncMaxInTx(100*time.Millisecond, 100).
+		AsyncTxDelays(time.Microsecond, time.Microsecond).
+		BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(ob.Directory(), "data.mdb"))
+		assert.NoErr(t, err)
+		assert.Eq(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	var box = model.BoxForTestStringIdEntity(ob)
+	for i := 0; i < 10; i++ {
+		_, err = box.Async().Put(&model.TestStringIdEntity{})
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, ob.AwaitAsyncCompletion())
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+}
+
+func TestBuilderOptionsInvalid(t *testing.T) {
+	for _, builder := range []*objectbox.Builder{
+		objectbox.NewBuilder().AsyncThrottle(500, 2*time.Hour),
+		objectbox.NewBuilder().AsyncMaxInTx(-time.Second, 100),
+		objectbox.NewBuilder().AsyncTxDelays(time.Microsecond, 72*time.Minute),
+	} {
+		ob, err := builder.TempDirectory().Model(model.ObjectBoxModel()).BuildOrError()
+		assert.Err(t, err)
+		assert.True(t, ob == nil)
+	}
+}
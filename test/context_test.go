@@ -0,0 +1,148 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestContextRead(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	iot.PutReadings(env.ObjectBox, 10)
+
+	var query = box.Query(iot.Reading_.ValueInteger.GreaterThan(10005))
+	defer query.Close()
+
+	objects, err := query.FindCtx(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, 5, len(objects.([]*iot.Reading)))
+
+	objects, err = box.GetAllCtx(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, 10, len(objects.([]*iot.Reading)))
+
+	object, err := box.GetCtx(context.Background(), 1)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), object.(*iot.Reading).Id)
+
+	objects, err = box.GetManyCtx(context.Background(), 1, 2, 999)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(objects.([]*iot.Reading)))
+	assert.True(t, objects.([]*iot.Reading)[2] == nil)
+
+	count, err := box.CountCtx(context.Background())
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	objects, err = query.FindCtx(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, objects == nil)
+
+	_, err = box.GetAllCtx(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	_, err = box.GetCtx(ctx, 1)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	_, err = box.GetManyCtx(ctx, 1, 2)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	_, err = box.CountCtx(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// cancelled in the middle of the iteration
+	for _, forEach := range []func(context.Context, func(interface{}) (bool, error)) error{box.ForEachCtx, query.ForEachCtx} {
+		ctx, cancel = context.WithCancel(context.Background())
+		var visited = 0
+		err = forEach(ctx, func(object interface{}) (bool, error) {
+			visited++
+			if visited == 2 {
+				cancel()
+			}
+			return true, nil
+		})
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Eq(t, 2, visited)
+	}
+}
+
+func TestContextTx(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	assert.NoErr(t, env.ObjectBox.RunInWriteTxCtx(context.Background(), func() error {
+		iot.PutReadings(env.ObjectBox, 2)
+		return nil
+	}))
+
+	// cancelled during the transaction - rolled back
+	ctx, cancel := context.WithCancel(context.Background())
+	var err = env.ObjectBox.RunInWriteTxCtx(ctx, func() error {
+		iot.PutReadings(env.ObjectBox, 2)
+		cancel()
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	// not started at all
+	var executed = false
+	err = env.ObjectBox.RunInReadTxCtx(ctx, func() error {
+		executed = true
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, !executed)
+}
+
+func TestContextAwaitAsync(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	_, err := box.Async().Put(&iot.Reading{ValueInteger: 1})
+	assert.NoErr(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoErr(t, box.Async().AwaitCompletionCtx(ctx))
+	assert.NoErr(t, env.ObjectBox.AwaitAsyncCompletionCtx(ctx))
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.Is(box.Async().AwaitCompletionCtx(ctx), context.Canceled))
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestDynamicBox(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var entity = model.Entity47()
+	entity.Related.Name = "related"
+	env.PutEntity(entity)
+
+	_, err := env.ObjectBox.DynamicBox("Missing")
+	assert.Err(t, err)
+
+	box, err := env.ObjectBox.DynamicBox("Entity")
+	assert.NoErr(t, err)
+
+	// read an object written using the generated binding
+	object, err := box.Get(entity.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, entity.Id, object["Id"])
+	assert.Eq(t, int64(47), object["Int"])
+	assert.Eq(t, int8(47), object["Int8"])
+	assert.Eq(t, uint32(47), object["Uint32"])
+	assert.Eq(t, entity.String, object["String"])
+	assert.Eq(t, entity.StringVector, object["StringVector"])
+	assert.Eq(t, entity.ByteVector, object["ByteVector"])
+	assert.Eq(t, entity.Float32, object["Float32"])
+	assert.Eq(t, entity.Related.Id, object["Related"])
+	assert.True(t, object["IntPtr"] == nil)
+
+	missing, err := box.Get(entity.Id + 100)
+	assert.NoErr(t, err)
+	assert.True(t, missing == nil)
+
+	// write a new object, read it using the generated binding
+	id, err := box.Put(map[string]interface{}{
+		"Int":          42,
+		"Int16":        int16(-1),
+		"Uint8":        255,
+		"Float64":      1.5,
+		"Bool":         true,
+		"String":       "dynamic",
+		"StringVector": []string{"a"},
+		"IntPtr":       nil,
+	})
+	assert.NoErr(t, err)
+
+	written, err := env.Box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 42, written.Int)
+	assert.Eq(t, int16(-1), written.Int16)
+	assert.Eq(t, uint8(255), written.Uint8)
+	assert.Eq(t, 1.5, written.Float64)
+	assert.Eq(t, true, written.Bool)
+	assert.Eq(t, "dynamic", written.String)
+	assert.Eq(t, []string{"a"}, written.StringVector)
+	assert.True(t, written.IntPtr == nil)
+
+	// the ID is set on the map
+	var object2 = map[string]interface{}{"String": "second"}
+	id, err = box.Put(object2)
+	assert.NoErr(t, err)
+	assert.Eq(t, id, object2["Id"])
+
+	// invalid values
+	_, err = box.Put(map[string]interface{}{"Unknown": 1})
+	assert.Err(t, err)
+	_, err = box.Put(map[string]interface{}{"Uint8": 256})
+	assert.Err(t, err)
+	_, err = box.Put(map[string]interface{}{"Int8": -1.5})
+	assert.Err(t, err)
+	_, err = box.Put(map[string]interface{}{"String": 1})
+	assert.Err(t, err)
+	_, err = box.Put(&model.Entity{})
+	assert.Err(t, err)
+
+	all, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(all))
+
+	// query using properties looked up by name
+	stringProperty, err := box.Property("String")
+	assert.NoErr(t, err)
+	_, err = box.Property("Missing")
+	assert.Err(t, err)
+
+	found, err := box.Query(objectbox.PropertyString{BaseProperty: stringProperty}.HasPrefix("dyn", true)).Find()
+	assert.NoErr(t, err)
+	var objects = found.([]map[string]interface{})
+	assert.Eq(t, 1, len(objects))
+	assert.Eq(t, "dynamic", objects[0]["String"])
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestBoxExportImportJSON(t *testing.T) {
+	env := model.NewTestEnv(t).SetOptions(model.TestEnvOptions{PopulateRelations: true})
+	defer env.Close()
+
+	env.Populate(5)
+
+	var exported bytes.Buffer
+	assert.NoErr(t, env.Box.ExportJSON(&exported))
+
+	// the output is readable without the Go structs
+	var objects []map[string]interface{}
+	assert.NoErr(t, json.Unmarshal(exported.Bytes(), &objects))
+	assert.Eq(t, 5, len(objects))
+	assert.Eq(t, float64(1), objects[0]["Id"])
+	assert.Eq(t, []interface{}{"first-1", "second-1", ""}, objects[0]["StringVector"])
+	assert.True(t, objects[0]["Related"].(float64) > 0)
+	assert.True(t, objects[0]["IntPtr"] == nil)
+
+	// standalone relations, keyed by the relation ID
+	var relations = objects[0]["relations"].(map[string]interface{})
+	assert.Eq(t, 2, len(relations))
+	assert.Eq(t, 1, len(relations["4"].([]interface{})))
+	assert.Eq(t, 1, len(relations["5"].([]interface{})))
+
+	env2 := model.NewTestEnv(t)
+	defer env2.Close()
+
+	ids, err := env2.Box.ImportJSON(bytes.NewReader(exported.Bytes()), objectbox.PutModeInsert)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{1, 2, 3, 4, 5}, ids)
+
+	var reExported bytes.Buffer
+	assert.NoErr(t, env2.Box.ExportJSON(&reExported))
+	assert.Eq(t, exported.String(), reExported.String())
+
+	relatedIds, err := env2.Box.RelationIds(model.Entity_.RelatedPtrSlice, 1)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{uint64(relations["5"].([]interface{})[0].(float64))}, relatedIds)
+
+	// insert fails for existing objects, as a whole
+	_, err = env2.Box.ImportJSON(bytes.NewReader(exported.Bytes()), objectbox.PutModeInsert)
+	assert.Err(t, err)
+
+	// update succeeds for existing objects
+	ids, err = env2.Box.ImportJSON(strings.NewReader(`[{"Id": 2, "String": "updated"}]`), objectbox.PutModeUpdate)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{2}, ids)
+
+	object, err := env2.Box.Get(2)
+	assert.NoErr(t, err)
+	assert.Eq(t, "updated", object.String)
+	assert.Eq(t, int64(0), object.Int64)
+
+	// relations given in the input replace the existing ones
+	_, err = env2.Box.ImportJSON(strings.NewReader(`[{"Id": 2, "relations": {"5": []}}]`), objectbox.PutModeUpdate)
+	assert.NoErr(t, err)
+	relatedIds, err = env2.Box.RelationIds(model.Entity_.RelatedPtrSlice, 2)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(relatedIds))
+	relatedIds, err = env2.Box.RelationIds(model.Entity_.RelatedSlice, 2)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(relatedIds))
+
+	// ...but fails for new ones
+	_, err = env2.Box.ImportJSON(strings.NewReader(`[{"String": "new"}]`), objectbox.PutModeUpdate)
+	assert.Err(t, err)
+
+	// objects without an ID are inserted as new
+	ids, err = env2.Box.ImportJSON(strings.NewReader(`[{"String": "new"}, {"Id": null, "Int": 1}]`), objectbox.PutModePut)
+	assert.NoErr(t, err)
+	assert.Eq(t, []uint64{6, 7}, ids)
+
+	count, err := env2.Box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(7), count)
+}
+
+func TestBoxExportImportJSONSpecialFloats(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.PutEntity(&model.Entity{Float32: float32(math.Inf(1)), Float64: math.NaN()})
+	env.PutEntity(&model.Entity{Float32: float32(math.Inf(-1)), Float64: 1.5})
+
+	var exported bytes.Buffer
+	assert.NoErr(t, env.Box.ExportJSON(&exported))
+
+	var objects []map[string]interface{}
+	assert.NoErr(t, json.Unmarshal(exported.Bytes(), &objects))
+	assert.Eq(t, "Infinity", objects[0]["Float32"])
+	assert.Eq(t, "NaN", objects[0]["Float64"])
+	assert.Eq(t, "-Infinity", objects[1]["Float32"])
+	assert.Eq(t, 1.5, objects[1]["Float64"])
+
+	env2 := model.NewTestEnv(t)
+	defer env2.Close()
+
+	_, err := env2.Box.ImportJSON(bytes.NewReader(exported.Bytes()), objectbox.PutModeInsert)
+	assert.NoErr(t, err)
+
+	object, err := env2.Box.Get(1)
+	assert.NoErr(t, err)
+	assert.True(t, math.IsInf(float64(object.Float32), 1))
+	assert.True(t, math.IsNaN(object.Float64))
+}
+
+func TestBoxImportJSONInvalid(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	for _, input := range []string{
+		`{"Id": 1}`,
+		`[{"Unknown": 1}]`,
+		`[{"Int8": 1000}]`,
+		`[{"String": 1}]`,
+		`[{"Float64": "infinite"}]`,
+		`[{"Id": 1}`,
+		`[{"relations": {"99": [1]}}]`,
+		`[{"relations": [1]}]`,
+	} {
+		_, err := env.Box.ImportJSON(strings.NewReader(input), objectbox.PutModePut)
+		assert.Err(t, err)
+	}
+
+	count, err := env.Box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+}
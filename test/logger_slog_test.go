@@ -0,0 +1,51 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	var logger = slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		Logger(objectbox.SlogLogger(logger)).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	assert.NoErr(t, ob.SetDebugFlags(objectbox.DebugflagsLogQueries))
+	_, err = model.BoxForEntity(ob).Query().Count()
+	assert.NoErr(t, err)
+
+	var line = buffer.String()
+	assert.True(t, strings.Contains(line, "level=DEBUG"))
+	assert.True(t, strings.Contains(line, `msg="query executed"`))
+	assert.True(t, strings.Contains(line, "entity=Entity"))
+	assert.True(t, strings.Contains(line, "operation=count"))
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+type testLogEntry struct {
+	level  objectbox.LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+type testLog struct {
+	mutex   sync.Mutex
+	entries []testLogEntry
+}
+
+func (log *testLog) log(level objectbox.LogLevel, msg string, fields map[string]interface{}) {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	log.entries = append(log.entries, testLogEntry{level, msg, fields})
+}
+
+// take returns and clears the logged entries
+func (log *testLog) take() []testLogEntry {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	var result = log.entries
+	log.entries = nil
+	return result
+}
+
+func TestLogger(t *testing.T) {
+	var log testLog
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).Logger(log.log).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+
+	// nothing is logged until enabled
+	_, err = box.Put(model.Entity47())
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(log.take()))
+
+	assert.NoErr(t, ob.SetDebugFlags(objectbox.DebugflagsLogTransactionsWrite))
+	assert.NoErr(t, ob.RunInWriteTx(func() error {
+		_, err := box.Put(model.Entity47())
+		return err
+	}))
+	var entries = log.take()
+	assert.Eq(t, 1, len(entries))
+	assert.Eq(t, objectbox.LogLevelDebug, entries[0].level)
+	assert.Eq(t, "write transaction committed", entries[0].msg)
+	assert.True(t, entries[0].fields["duration"] != nil)
+
+	assert.NoErr(t, ob.SetDebugFlags(objectbox.DebugflagsLogQueries|objectbox.DebugflagsLogQueryParameters))
+	count, err := box.Query(model.Entity_.Int.Equals(47)).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+	entries = log.take()
+	assert.Eq(t, 1, len(entries))
+	assert.Eq(t, "query executed", entries[0].msg)
+	assert.Eq(t, "Entity", entries[0].fields["entity"])
+	assert.Eq(t, "count", entries[0].fields["operation"])
+	assert.True(t, entries[0].fields["params"] != nil)
+
+	assert.NoErr(t, ob.SetDebugFlags(objectbox.DebugflagsLogAsyncQueue))
+	id, err := model.BoxForTestStringIdEntity(ob).Async().Put(&model.TestStringIdEntity{})
+	assert.NoErr(t, err)
+	assert.NoErr(t, ob.AwaitAsyncCompletion())
+	entries = log.take()
+	assert.Eq(t, 1, len(entries))
+	assert.Eq(t, "async operation submitted", entries[0].msg)
+	assert.Eq(t, "put", entries[0].fields["operation"])
+	assert.Eq(t, id, entries[0].fields["id"])
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestMigration(t *testing.T) {
+	var dir = t.TempDir()
+	var executed []uint
+
+	// each migration puts an object so that we can verify the transaction was committed
+	var migrationTo = func(version uint) func(tx *objectbox.Tx) error {
+		return func(tx *objectbox.Tx) error {
+			executed = append(executed, version)
+			box, err := tx.Box(model.EntityBinding.Id)
+			if err != nil {
+				return err
+			}
+			_, err = box.Put(&model.Entity{Int: int(version)})
+			return err
+		}
+	}
+
+	var open = func(migrations ...uint) (*objectbox.ObjectBox, error) {
+		var builder = objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel())
+		for _, to := range migrations {
+			builder.Migration(to-1, to, migrationTo(to))
+		}
+		return builder.BuildOrError()
+	}
+
+	var count = func(ob *objectbox.ObjectBox) uint64 {
+		count, err := model.BoxForEntity(ob).Count()
+		assert.NoErr(t, err)
+		return count
+	}
+
+	// a database created without migrations is considered version 0
+	ob, err := open()
+	assert.NoErr(t, err)
+	ob.Close()
+
+	ob, err = open(1, 2)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint{1, 2}, executed)
+	assert.Eq(t, uint64(2), count(ob))
+	ob.Close()
+
+	// already at the latest version
+	executed = nil
+	ob, err = open(1, 2)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(executed))
+	ob.Close()
+
+	// a failing migration is rolled back and the version isn't changed
+	ob, err = objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		Migration(2, 3, func(tx *objectbox.Tx) error {
+			if err := migrationTo(3)(tx); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		}).BuildOrError()
+	assert.Err(t, err)
+
+	executed = nil
+	ob, err = open(1, 2, 3)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint{3}, executed)
+	assert.Eq(t, uint64(3), count(ob))
+	ob.Close()
+
+	// the database is newer than the known migrations
+	_, err = open(1, 2)
+	assert.Err(t, err)
+
+	// a new database starts at the latest version
+	executed = nil
+	ob, err = objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		Migration(0, 1, migrationTo(1)).BuildOrError()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(executed))
+	assert.Eq(t, uint64(0), count(ob))
+	ob.Close()
+}
+
+func TestMigrationVersionInDatabase(t *testing.T) {
+	var dir = t.TempDir()
+	var executed = 0
+	var open = func(dir string) *objectbox.ObjectBox {
+		ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+			Migration(0, 1, func(tx *objectbox.Tx) error {
+				executed++
+				return nil
+			}).BuildOrError()
+		assert.NoErr(t, err)
+		return ob
+	}
+
+	// version 0
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	ob.Close()
+
+	ob = open(dir)
+	ob.Close()
+	assert.Eq(t, 1, executed)
+
+	// the version is a part of the database file, no other files are necessary to keep it
+	var copyDir = t.TempDir()
+	data, err := ioutil.ReadFile(filepath.Join(dir, "data.mdb"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(copyDir, "data.mdb"), data, 0644))
+
+	ob = open(copyDir)
+	defer ob.Close()
+	assert.Eq(t, 1, executed)
+
+	// the internal entity isn't visible
+	for _, entity := range ob.Entities() {
+		assert.True(t, entity.Name() != "ObjectBoxGoSchema")
+	}
+}
+
+func TestMigrationRestoredBackup(t *testing.T) {
+	var dir = t.TempDir()
+	var executed []uint
+	var migrationTo = func(version uint) func(tx *objectbox.Tx) error {
+		return func(tx *objectbox.Tx) error {
+			executed = append(executed, version)
+			return nil
+		}
+	}
+
+	// a backup of a database at version 1
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		Migration(0, 1, migrationTo(1)).BuildOrError()
+	assert.NoErr(t, err)
+	_, err = model.BoxForEntity(ob).Put(&model.Entity{})
+	assert.NoErr(t, err)
+	var backupFile = filepath.Join(t.TempDir(), "backup.obx")
+	assert.NoErr(t, ob.BackupToFile(backupFile))
+	ob.Close()
+
+	// the restored database isn't considered new, i.e. it's migrated from the backup's version
+	restored, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		RestoreFromFile(backupFile).
+		Migration(0, 1, migrationTo(1)).
+		Migration(1, 2, migrationTo(2)).
+		BuildOrError()
+	assert.NoErr(t, err)
+	defer restored.Close()
+	assert.Eq(t, []uint{2}, executed)
+}
+
+func TestMigrationInvalid(t *testing.T) {
+	var noop = func(tx *objectbox.Tx) error { return nil }
+
+	_, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		Migration(1, 1, noop).BuildOrError()
+	assert.Err(t, err)
+
+	_, err = objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).
+		Migration(0, 1, noop).Migration(0, 2, noop).BuildOrError()
+	assert.Err(t, err)
+
+	// missing migration from version 0 to 1
+	var dir = t.TempDir()
+	ob, err := objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	ob.Close()
+
+	_, err = objectbox.NewBuilder().Directory(dir).Model(model.ObjectBoxModel()).
+		Migration(1, 2, noop).BuildOrError()
+	assert.Err(t, err)
+}
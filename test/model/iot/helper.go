@@ -19,40 +19,22 @@ package iot
 import (
 	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/assert"
-	"io/ioutil"
-	"os"
 	"strconv"
 )
 
 type TestEnv struct {
 	*objectbox.ObjectBox
-	dir string
-}
-
-// Close closes ObjectBox and removes the database
-func (env *TestEnv) Close() {
-	env.ObjectBox.Close()
-	os.RemoveAll(env.dir)
 }
 
 // NewTestEnv creates an empty ObjectBox instance
 func NewTestEnv() *TestEnv {
-
 	// Test in a temporary directory - if tested by an end user, the repo is read-only.
-	tempDir, err := ioutil.TempDir("", "objectbox-test")
-	if err != nil {
-		panic(err)
-	}
-
-	objectBox, err := objectbox.NewBuilder().Directory(tempDir).Model(ObjectBoxModel()).Build()
+	objectBox, err := objectbox.NewBuilder().TempDirectory().Model(ObjectBoxModel()).Build()
 	if err != nil {
 		panic(err)
 	}
 
-	return &TestEnv{
-		ObjectBox: objectBox,
-		dir:       tempDir,
-	}
+	return &TestEnv{ObjectBox: objectBox}
 }
 
 // PutEvent creates an event
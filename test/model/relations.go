@@ -0,0 +1,136 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+)
+
+// This file implements objectbox.ObjectBindingRelations for the generated bindings of entities with relations,
+// allowing Query.NoEager() on them. It's kept next to the generated code because objectbox-generator doesn't emit it.
+
+// LoadNoEager is like Load() but leaves the relation fields empty
+func (entity_EntityInfo) LoadNoEager(ob *objectbox.ObjectBox, bytes []byte) (interface{}, error) {
+	if len(bytes) == 0 { // sanity check, should "never" happen
+		return nil, errors.New("can't deserialize an object of type 'Entity' - no data received")
+	}
+
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+
+	var propId = table.GetUint64Slot(4, 0)
+
+	propDate, err := objectbox.TimeInt64ConvertToEntityProperty(fbutils.GetInt64Slot(table, 40))
+	if err != nil {
+		return nil, errors.New("converter objectbox.TimeInt64ConvertToEntityProperty() failed on Entity.Date: " + err.Error())
+	}
+
+	propComplex128, err := complex128BytesToEntityProperty(fbutils.GetByteVectorSlot(table, 42))
+	if err != nil {
+		return nil, errors.New("converter complex128BytesToEntityProperty() failed on Entity.Complex128: " + err.Error())
+	}
+
+	return &Entity{
+		Id:              propId,
+		Int:             fbutils.GetIntSlot(table, 6),
+		Int8:            fbutils.GetInt8Slot(table, 8),
+		Int16:           fbutils.GetInt16Slot(table, 10),
+		Int32:           fbutils.GetInt32Slot(table, 12),
+		Int64:           fbutils.GetInt64Slot(table, 14),
+		Uint:            fbutils.GetUintSlot(table, 16),
+		Uint8:           fbutils.GetUint8Slot(table, 18),
+		Uint16:          fbutils.GetUint16Slot(table, 20),
+		Uint32:          fbutils.GetUint32Slot(table, 22),
+		Uint64:          fbutils.GetUint64Slot(table, 24),
+		Bool:            fbutils.GetBoolSlot(table, 26),
+		String:          fbutils.GetStringSlot(table, 28),
+		StringVector:    fbutils.GetStringVectorSlot(table, 44),
+		Byte:            fbutils.GetByteSlot(table, 30),
+		ByteVector:      fbutils.GetByteVectorSlot(table, 32),
+		Rune:            fbutils.GetRuneSlot(table, 34),
+		Float32:         fbutils.GetFloat32Slot(table, 36),
+		Float64:         fbutils.GetFloat64Slot(table, 38),
+		Date:            propDate,
+		Complex128:      propComplex128,
+		IntPtr:          fbutils.GetIntPtrSlot(table, 52),
+		Int8Ptr:         fbutils.GetInt8PtrSlot(table, 54),
+		Int16Ptr:        fbutils.GetInt16PtrSlot(table, 56),
+		Int32Ptr:        fbutils.GetInt32PtrSlot(table, 58),
+		Int64Ptr:        fbutils.GetInt64PtrSlot(table, 60),
+		UintPtr:         fbutils.GetUintPtrSlot(table, 62),
+		Uint8Ptr:        fbutils.GetUint8PtrSlot(table, 64),
+		Uint16Ptr:       fbutils.GetUint16PtrSlot(table, 66),
+		Uint32Ptr:       fbutils.GetUint32PtrSlot(table, 68),
+		Uint64Ptr:       fbutils.GetUint64PtrSlot(table, 70),
+		BoolPtr:         fbutils.GetBoolPtrSlot(table, 72),
+		StringPtr:       fbutils.GetStringPtrSlot(table, 74),
+		StringVectorPtr: fbutils.GetStringVectorPtrSlot(table, 88),
+		BytePtr:         fbutils.GetBytePtrSlot(table, 78),
+		ByteVectorPtr:   fbutils.GetByteVectorPtrSlot(table, 90),
+		RunePtr:         fbutils.GetRunePtrSlot(table, 82),
+		Float32Ptr:      fbutils.GetFloat32PtrSlot(table, 84),
+		Float64Ptr:      fbutils.GetFloat64PtrSlot(table, 86),
+	}, nil
+}
+
+// SetRelated assigns the targets of a standalone relation, as read by Query.Eager()
+func (entity_EntityInfo) SetRelated(object interface{}, relationId objectbox.TypeId, targets interface{}) error {
+	switch relationId {
+	case Entity_.RelatedSlice.Id:
+		object.(*Entity).RelatedSlice = targets.([]EntityByValue)
+	case Entity_.RelatedPtrSlice.Id:
+		object.(*Entity).RelatedPtrSlice = targets.([]*TestEntityRelated)
+	default:
+		return fmt.Errorf("unknown relation %d on entity Entity", relationId)
+	}
+	return nil
+}
+
+// LoadNoEager is like Load() but leaves the relation fields empty
+func (testEntityRelated_EntityInfo) LoadNoEager(ob *objectbox.ObjectBox, bytes []byte) (interface{}, error) {
+	if len(bytes) == 0 { // sanity check, should "never" happen
+		return nil, errors.New("can't deserialize an object of type 'TestEntityRelated' - no data received")
+	}
+
+	var table = &flatbuffers.Table{
+		Bytes: bytes,
+		Pos:   flatbuffers.GetUOffsetT(bytes),
+	}
+
+	return &TestEntityRelated{
+		Id:   table.GetUint64Slot(4, 0),
+		Name: fbutils.GetStringSlot(table, 6),
+	}, nil
+}
+
+// SetRelated assigns the targets of a standalone relation, as read by Query.Eager()
+func (testEntityRelated_EntityInfo) SetRelated(object interface{}, relationId objectbox.TypeId, targets interface{}) error {
+	switch relationId {
+	case TestEntityRelated_.NextSlice.Id:
+		object.(*TestEntityRelated).NextSlice = targets.([]EntityByValue)
+	default:
+		return fmt.Errorf("unknown relation %d on entity TestEntityRelated", relationId)
+	}
+	return nil
+}
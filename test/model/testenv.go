@@ -18,7 +18,6 @@ package model
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
@@ -45,30 +44,22 @@ type TestEnvOptions struct {
 // NewTestEnv creates the test environment
 func NewTestEnv(t *testing.T) *TestEnv {
 	// Test in a temporary directory - if tested by an end user, the repo is read-only.
-	tempDir, err := ioutil.TempDir("", "objectbox-test")
+	objectBox, err := objectbox.NewBuilder().TempDirectory().Model(ObjectBoxModel()).Build()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	var env = &TestEnv{
-		Directory: tempDir,
+	return &TestEnv{
+		ObjectBox: objectBox,
+		Box:       BoxForEntity(objectBox),
+		Directory: objectBox.Directory(),
 		t:         t,
 	}
-
-	env.ObjectBox, err = objectbox.NewBuilder().Directory(env.Directory).Model(ObjectBoxModel()).Build()
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	env.Box = BoxForEntity(env.ObjectBox)
-
-	return env
 }
 
 // Close closes ObjectBox and removes the database
 func (env *TestEnv) Close() {
 	env.ObjectBox.Close()
-	os.RemoveAll(env.Directory)
 }
 
 func (env *TestEnv) SyncClient(serverUri string) *objectbox.SyncClient {
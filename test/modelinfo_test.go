@@ -0,0 +1,71 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestModelInfo(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var entities = env.ObjectBox.Entities()
+	assert.Eq(t, 5, len(entities))
+	for i := 1; i < len(entities); i++ {
+		assert.True(t, entities[i-1].Id() < entities[i].Id())
+	}
+
+	var entity = env.ObjectBox.Entity("Entity")
+	assert.True(t, entity != nil)
+	assert.Eq(t, model.EntityBinding.Id, entity.Id())
+	assert.Eq(t, "Entity", entity.Name())
+	assert.True(t, env.ObjectBox.Entity("Missing") == nil)
+
+	var properties = entity.Properties()
+	assert.Eq(t, "Id", properties[0].Name)
+	assert.Eq(t, objectbox.PropertyTypeLong, properties[0].Type)
+	assert.Eq(t, objectbox.PropertyFlagId, properties[0].Flags)
+	assert.Eq(t, "Long", properties[0].Type.String())
+	assert.Eq(t, "Id", properties[0].Flags.String())
+
+	var uint8Property = entity.Property("Uint8")
+	assert.Eq(t, model.Entity_.Uint8.Id, uint8Property.Id)
+	assert.Eq(t, objectbox.PropertyTypeByte, uint8Property.Type)
+	assert.Eq(t, objectbox.PropertyFlagUnsigned, uint8Property.Flags)
+	assert.Eq(t, objectbox.TypeId(0), uint8Property.IndexId)
+
+	var related = entity.Property("Related")
+	assert.Eq(t, objectbox.PropertyTypeRelation, related.Type)
+	assert.Eq(t, "Indexed|IndexPartialSkipZero|Unsigned", related.Flags.String())
+	assert.Eq(t, objectbox.TypeId(1), related.IndexId)
+	assert.Eq(t, "TestEntityRelated", related.RelationTarget)
+	assert.True(t, entity.Property("Missing") == nil)
+
+	// returned values are copies
+	properties[0].Name = "changed"
+	assert.Eq(t, "Id", entity.Properties()[0].Name)
+
+	assert.Eq(t, []objectbox.RelationInfo{
+		{Id: 4, TargetEntityId: model.EntityByValueBinding.Id},
+		{Id: 5, TargetEntityId: model.TestEntityRelatedBinding.Id},
+	}, entity.Relations())
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestObserver(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var all = make(chan string, 10)
+	observerAll, err := env.ObjectBox.Subscribe(func(entityIds []objectbox.TypeId) {
+		all <- fmt.Sprint(entityIds)
+	})
+	assert.NoErr(t, err)
+
+	var events = make(chan string, 10)
+	observerEvents, err := env.ObjectBox.Subscribe(func(entityIds []objectbox.TypeId) {
+		events <- fmt.Sprint(entityIds)
+	}, iot.EventBinding.Id)
+	assert.NoErr(t, err)
+
+	iot.PutReadings(env.ObjectBox, 1)
+	assert.StringChannelExpect(t, fmt.Sprint([]objectbox.TypeId{iot.ReadingBinding.Id}), all, time.Second)
+	assert.StringChannelMustTimeout(t, events, 100*time.Millisecond)
+
+	iot.PutEvents(env.ObjectBox, 1)
+	assert.StringChannelExpect(t, fmt.Sprint([]objectbox.TypeId{iot.EventBinding.Id}), all, time.Second)
+	assert.StringChannelExpect(t, fmt.Sprint([]objectbox.TypeId{iot.EventBinding.Id}), events, time.Second)
+
+	// read transactions don't trigger notifications
+	_, err = iot.BoxForEvent(env.ObjectBox).GetAll()
+	assert.NoErr(t, err)
+	assert.StringChannelMustTimeout(t, all, 100*time.Millisecond)
+
+	assert.NoErr(t, observerAll.Unsubscribe())
+	assert.NoErr(t, observerAll.Unsubscribe())
+	iot.PutEvents(env.ObjectBox, 1)
+	assert.StringChannelExpect(t, fmt.Sprint([]objectbox.TypeId{iot.EventBinding.Id}), events, time.Second)
+	assert.StringChannelMustTimeout(t, all, 100*time.Millisecond)
+
+	assert.NoErr(t, observerEvents.Unsubscribe())
+
+	// unknown entity
+	_, err = env.ObjectBox.Subscribe(func([]objectbox.TypeId) {}, 999)
+	assert.Err(t, err)
+}
+
+func TestQueryObserver(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var query = iot.BoxForReading(env.ObjectBox).Query(iot.Reading_.ValueInteger.GreaterThan(10001))
+	defer query.Close()
+
+	var results = make(chan string, 10)
+	observer, err := query.Subscribe(func(objects interface{}, err error) {
+		if err != nil {
+			results <- err.Error()
+		} else {
+			results <- fmt.Sprint(len(objects.([]*iot.Reading)))
+		}
+	})
+	assert.NoErr(t, err)
+	defer observer.Unsubscribe()
+
+	// initial results
+	assert.StringChannelExpect(t, "0", results, time.Second)
+
+	// a single transaction results in a single notification
+	assert.NoErr(t, env.ObjectBox.RunInWriteTx(func() error {
+		iot.PutReadings(env.ObjectBox, 3)
+		return nil
+	}))
+	assert.StringChannelExpect(t, "2", results, time.Second)
+
+	// changes to other entities don't trigger the query observer
+	iot.PutEvents(env.ObjectBox, 1)
+	assert.StringChannelMustTimeout(t, results, 100*time.Millisecond)
+}
+
+func TestObserverCloseWaitsForCallback(t *testing.T) {
+	env := iot.NewTestEnv()
+
+	var started = make(chan string, 1)
+	var finished uint32
+	var observer *objectbox.Observer
+	var err error
+	observer, err = env.ObjectBox.Subscribe(func([]objectbox.TypeId) {
+		// must not wait for the callback itself
+		assert.NoErr(t, observer.Unsubscribe())
+		started <- "started"
+		time.Sleep(100 * time.Millisecond)
+		atomic.StoreUint32(&finished, 1)
+	})
+	assert.NoErr(t, err)
+
+	iot.PutEvents(env.ObjectBox, 1)
+	assert.StringChannelExpect(t, "started", started, time.Second)
+
+	env.Close()
+	assert.Eq(t, uint32(1), atomic.LoadUint32(&finished))
+}
+
+func TestQueryObserverPrivateQuery(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var query = iot.BoxForReading(env.ObjectBox).Query(iot.Reading_.ValueInteger.GreaterThan(0))
+	assert.NoErr(t, query.SetInt64Params(iot.Reading_.ValueInteger, 10001))
+
+	var results = make(chan string, 10)
+	observer, err := query.Subscribe(func(objects interface{}, err error) {
+		if err != nil {
+			results <- err.Error()
+		} else {
+			results <- fmt.Sprint(len(objects.([]*iot.Reading)))
+		}
+	})
+	assert.NoErr(t, err)
+	defer observer.Unsubscribe()
+	assert.StringChannelExpect(t, "0", results, time.Second)
+
+	// the observer uses its own copy of the query, with the parameters set at the time of subscribing
+	assert.NoErr(t, query.SetInt64Params(iot.Reading_.ValueInteger, 0))
+	assert.NoErr(t, query.Close())
+
+	assert.NoErr(t, env.ObjectBox.RunInWriteTx(func() error {
+		iot.PutReadings(env.ObjectBox, 3)
+		return nil
+	}))
+	assert.StringChannelExpect(t, "2", results, time.Second)
+}
+
+func TestObserverClosedStore(t *testing.T) {
+	env := iot.NewTestEnv()
+	var query = iot.BoxForReading(env.ObjectBox).Query()
+	defer query.Close()
+	env.Close()
+
+	_, err := env.ObjectBox.Subscribe(func([]objectbox.TypeId) {})
+	assert.Err(t, err)
+
+	_, err = query.Subscribe(func(interface{}, error) {})
+	assert.Err(t, err)
+}
@@ -0,0 +1,308 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestPropertyQueryAggregates(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	// values 10001..10010
+	iot.PutReadings(env.ObjectBox, 10)
+
+	var query = box.Query()
+	defer query.Close()
+
+	var pq = query.Property(iot.Reading_.ValueInteger)
+	defer pq.Close()
+
+	count, err := pq.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	minInt, err := pq.MinInt()
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(10001), minInt)
+
+	maxInt, err := pq.MaxInt()
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(10010), maxInt)
+
+	sumInt, err := pq.SumInt()
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(100055), sumInt)
+
+	avg, err := pq.Average()
+	assert.NoErr(t, err)
+	assert.Eq(t, float64(10005.5), avg)
+
+	var pqFloat = query.Property(iot.Reading_.ValueFloating)
+	defer pqFloat.Close()
+
+	min, err := pqFloat.Min()
+	assert.NoErr(t, err)
+	assert.Eq(t, float64(10001), min)
+
+	max, err := pqFloat.Max()
+	assert.NoErr(t, err)
+	assert.Eq(t, float64(10010), max)
+
+	sum, err := pqFloat.Sum()
+	assert.NoErr(t, err)
+	assert.Eq(t, float64(100055), sum)
+
+	// aggregates respect query conditions
+	var queryFiltered = box.Query(iot.Reading_.ValueInt32.GreaterThan(10005))
+	defer queryFiltered.Close()
+
+	sumInt, err = queryFiltered.Property(iot.Reading_.ValueInt32).SumInt()
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(50040), sumInt)
+
+	avg, err = queryFiltered.Property(iot.Reading_.ValueFloating32).Average()
+	assert.NoErr(t, err)
+	assert.Eq(t, float64(10008), avg)
+}
+
+func TestPropertyQueryCountDistinct(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	iot.PutReadings(env.ObjectBox, 10)
+	iot.PutReading(env.ObjectBox, "duplicate", "string1", 10001, 10001, 10001, 10001)
+
+	var pq = box.Query().Property(iot.Reading_.ValueInteger)
+
+	count, err := pq.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(11), count)
+
+	count, err = pq.Distinct(true).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	count, err = pq.Distinct(false).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(11), count)
+}
+
+func TestPropertyQueryFind(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	iot.PutReadings(env.ObjectBox, 3)
+	iot.PutReading(env.ObjectBox, "duplicate", "STRING1", 10001, 10001, 10001, 10001)
+
+	var query = box.Query()
+
+	strings, err := query.Property(iot.Reading_.ValueString).FindStrings(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []string{"string1", "string2", "string3", "STRING1"}, strings)
+
+	strings, err = query.Property(iot.Reading_.ValueString).DistinctString(true, true).FindStrings(nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, len(strings))
+
+	strings, err = query.Property(iot.Reading_.ValueString).DistinctString(true, false).FindStrings(nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(strings))
+
+	int64s, err := query.Property(iot.Reading_.ValueInteger).FindInt64s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []int64{10001, 10002, 10003, 10001}, int64s)
+
+	int64s, err = query.Property(iot.Reading_.ValueInteger).Distinct(true).FindInt64s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []int64{10001, 10002, 10003}, int64s)
+
+	int32s, err := box.Query(iot.Reading_.ValueInt32.GreaterThan(10001)).Property(iot.Reading_.ValueInt32).FindInt32s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []int32{10002, 10003}, int32s)
+
+	float64s, err := query.Property(iot.Reading_.ValueFloating).Distinct(true).FindFloat64s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []float64{10001, 10002, 10003}, float64s)
+
+	float32s, err := query.Property(iot.Reading_.ValueFloating32).Distinct(true).FindFloat32s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []float32{10001, 10002, 10003}, float32s)
+}
+
+func TestPropertyQueryFindNil(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var str = "value"
+	var i64 = int64(42)
+	env.PutEntity(&model.Entity{StringPtr: &str, Int64Ptr: &i64})
+	env.PutEntity(&model.Entity{})
+
+	var query = env.Box.Query()
+
+	strings, err := query.Property(model.Entity_.StringPtr).FindStrings(nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"value"}, strings)
+
+	var strIfNil = "nil"
+	strings, err = query.Property(model.Entity_.StringPtr).FindStrings(&strIfNil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []string{"value", "nil"}, strings)
+
+	int64s, err := query.Property(model.Entity_.Int64Ptr).FindInt64s(nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, []int64{42}, int64s)
+
+	var i64IfNil = int64(-1)
+	int64s, err = query.Property(model.Entity_.Int64Ptr).FindInt64s(&i64IfNil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []int64{42, -1}, int64s)
+}
+
+func TestPropertyQueryInvalid(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	box := iot.BoxForReading(env.ObjectBox)
+
+	var query = box.Query()
+
+	// property of another entity
+	_, err := query.PropertyOrError(iot.Event_.Date)
+	assert.Err(t, err)
+
+	var pq = query.Property(iot.Reading_.ValueInteger)
+	assert.NoErr(t, pq.Close())
+	_, err = pq.Count()
+	assert.Err(t, err)
+}
+
+func TestPropertyQueryUnsigned(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.PutEntity(&model.Entity{Uint64: 1, Uint32: 10, Uint16: 100, Uint8: 200})
+	env.PutEntity(&model.Entity{Uint64: 1 << 40, Uint32: 1 << 31, Uint16: 1 << 15, Uint8: 1 << 7})
+
+	var query = env.Box.Query()
+
+	minUint, err := query.Property(model.Entity_.Uint64).MinUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), minUint)
+
+	maxUint, err := query.Property(model.Entity_.Uint64).MaxUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1<<40), maxUint)
+
+	sumUint, err := query.Property(model.Entity_.Uint64).SumUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1<<40+1), sumUint)
+
+	uint64s, err := query.Property(model.Entity_.Uint64).FindUint64s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{1, 1 << 40}, uint64s)
+
+	maxUint32, err := query.Property(model.Entity_.Uint32).MaxUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1<<31), maxUint32)
+
+	uint32s, err := query.Property(model.Entity_.Uint32).FindUint32s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint32{10, 1 << 31}, uint32s)
+
+	uint16s, err := query.Property(model.Entity_.Uint16).FindUint16s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint16{100, 1 << 15}, uint16s)
+
+	uint8s, err := query.Property(model.Entity_.Uint8).FindUint8s(nil)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint8{200, 1 << 7}, uint8s)
+}
+
+func TestPropertyQueryUnsignedLarge(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	// values not fitting into an int64
+	env.PutEntity(&model.Entity{Uint64: 1})
+	env.PutEntity(&model.Entity{Uint64: 1<<63 + 5})
+
+	var query = env.Box.Query()
+
+	minUint, err := query.Property(model.Entity_.Uint64).MinUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), minUint)
+
+	maxUint, err := query.Property(model.Entity_.Uint64).MaxUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1<<63+5), maxUint)
+
+	sumUint, err := query.Property(model.Entity_.Uint64).SumUint()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1<<63+6), sumUint)
+
+	env.PutEntity(&model.Entity{Uint64: 1 << 63})
+	_, err = query.Property(model.Entity_.Uint64).SumUint()
+	assert.Err(t, err)
+}
+
+func TestPropertyQueryDistinctUnsupported(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+	iot.PutReadings(env.ObjectBox, 3)
+
+	var query = iot.BoxForReading(env.ObjectBox).Query()
+	defer query.Close()
+
+	// aggregate functions don't support distinct
+	_, err := query.Property(iot.Reading_.ValueInteger).Distinct(true).SumInt()
+	assert.Err(t, err)
+	_, err = query.Property(iot.Reading_.ValueFloating).Distinct(true).Average()
+	assert.Err(t, err)
+
+	// DistinctString() only works on strings
+	_, err = query.Property(iot.Reading_.ValueInteger).DistinctString(true, true).Count()
+	assert.Err(t, err)
+
+	// disabling distinct makes the property query usable again
+	var pq = query.Property(iot.Reading_.ValueInteger).Distinct(true)
+	sum, err := pq.Distinct(false).SumInt()
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(30006), sum)
+}
+
+func TestPropertyQueryClosedWithQuery(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var query = iot.BoxForReading(env.ObjectBox).Query()
+	var pq = query.Property(iot.Reading_.ValueInteger)
+	assert.NoErr(t, query.Close())
+
+	// the property query was closed by its query
+	_, err := pq.Count()
+	assert.Err(t, err)
+	assert.NoErr(t, pq.Close())
+}
@@ -397,9 +397,49 @@ func TestQueryParams(t *testing.T) {
 			func(q i) error { return eq(q).SetInt64ParamsIn(E.Related, 1) }},
 		{999, s{`Related not in [1]`}, box.Query(E.Related.NotIn()),
 			func(q i) error { return eq(q).SetInt64ParamsIn(E.Related, 1) }},
+
+		{256, s{`Bool == 1`}, box.Query(E.Bool.Equals(false)),
+			func(q i) error { return eq(q).SetBoolParams(E.Bool, true) }},
+		{744, s{`Bool == 0`}, box.Query(E.Bool.Equals(true)),
+			func(q i) error { return eq(q).SetBoolParams(E.Bool, false) }},
+
+		{2, s{`Uint64 == 47`}, box.Query(E.Uint64.Equals(0)),
+			func(q i) error { return eq(q).SetUint64Params(E.Uint64, e.Uint64) }},
+		{2, s{`Uint64 in [94|47]`, `Uint64 in [47|94]`}, box.Query(E.Uint64.In()),
+			func(q i) error { return eq(q).SetUint64ParamsIn(E.Uint64, e.Uint64, e.Uint64*2) }},
+		{1, s{`Related == 1`}, box.Query(E.Related.Equals(0)),
+			func(q i) error { return eq(q).SetUint64Params(E.Related, 1) }},
+		{1, s{`Id between 1 and 1`}, box.Query(E.Id.Between(0, 0)),
+			func(q i) error { return eq(q).SetUint64Params(E.Id, 1, 1) }},
+
+		{2, s{`Float32 between 47.739990 and 47.740013`}, box.Query(E.Float32.Between(0, 0)),
+			func(q i) error { return eq(q).SetFloat32Params(E.Float32, e.Float32-0.00001, e.Float32+0.00001) }},
+		{498, s{`Float32 > 47.740002`}, box.Query(E.Float32.GreaterThan(0)),
+			func(q i) error { return eq(q).SetFloat32Params(E.Float32, e.Float32) }},
+
+		{2, s{`StringVector contains "first-1"`}, box.Query(E.StringVector.Contains("", true)),
+			func(q i) error { return eq(q).SetStringVectorParams(E.StringVector, "first-1") }},
 	})
 }
 
+func TestQueryParamsErrors(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+	var query = env.Box.Query(E.Uint64.Between(0, 0), E.Float32.Between(0, 0))
+
+	assert.Err(t, query.SetUint64Params(E.Uint64))
+	assert.Err(t, query.SetUint64Params(E.Uint64, 1, 2, 3))
+	assert.Err(t, query.SetUint64ParamsIn(E.Uint64))
+	assert.Err(t, query.SetFloat32Params(E.Float32))
+	assert.Err(t, query.SetFloat32Params(E.Float32, 1, 2, 3))
+	assert.Err(t, query.SetBoolParams(model.TestEntityRelated_.Name, true))
+
+	assert.NoErr(t, query.SetUint64Params(E.Uint64, 1, 2))
+	assert.NoErr(t, query.SetFloat32Params(E.Float32, 1, 2))
+}
+
 func TestQueryAlias(t *testing.T) {
 	env := model.NewTestEnv(t)
 	defer env.Close()
@@ -1183,6 +1223,17 @@ func executeTestCase(t *testing.T, env *model.TestEnv, options queryTestOptions,
 		matchAllEntityIds(t, ids, actualData)
 	}
 
+	// ForEach
+	var visitedCount = 0
+	if err := query.ForEach(func(object interface{}) (bool, error) {
+		visitedCount++
+		return true, nil
+	}); err != nil {
+		assert.Failf(t, "case #%d {%s} %s", i, desc, err)
+	} else if visitedCount != count {
+		assert.Failf(t, "case #%d {%s} expected %d, but got %d objects visited by ForEach()", i, desc, count, visitedCount)
+	}
+
 	// Remove
 	if !options.skipRemove {
 		if removedCount, err := query.Remove(); err != nil {
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestQueryDescribe(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+	var R = model.TestEntityRelated_
+	var B = model.EntityByValue_
+
+	var query = env.Box.Query(
+		E.Int.GreaterThan(1),
+		E.Related.Equals(2),
+		E.Related.Link(R.Name.Equals("rel", true)),
+		E.RelatedSlice.Link(B.Text.HasPrefix("by", false)),
+		E.Int64.OrderDesc(),
+		E.String.OrderAsc(true),
+		E.String.OrderNilLast(),
+	)
+
+	description, err := query.Describe()
+	assert.NoErr(t, err)
+
+	var lines = strings.Split(description, "\n")
+	var contains = func(expected string) {
+		for _, line := range lines {
+			if line == expected {
+				return
+			}
+		}
+		t.Errorf("line %q not found in the description:\n%s", expected, description)
+	}
+
+	params, err := query.DescribeParams()
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(description, strings.TrimSpace(params)))
+
+	contains("Order: Int64 (descending), String (ascending, case sensitive, nil last)")
+	contains("Links:")
+	contains("  link to TestEntityRelated over property Related, conditions on Name")
+	contains("  many-to-many link to EntityByValue over relation 4, conditions on Text")
+	contains("Indexed properties in conditions: Entity.Related")
+
+	// without any conditions
+	description, err = env.Box.Query().Describe()
+	assert.NoErr(t, err)
+	assert.True(t, strings.HasSuffix(description, "\nIndexed properties in conditions: none"))
+	assert.True(t, !strings.Contains(description, "Order:"))
+
+	// order properties are listed by precedence, i.e. in the order of the calls, not by their IDs
+	var ordered = env.Box.Query(E.String.OrderAsc(false), E.Int64.OrderAsc())
+	description, err = ordered.Describe()
+	assert.NoErr(t, err)
+	assert.NoErr(t, ordered.Close())
+	lines = strings.Split(description, "\n")
+	contains("Order: String (ascending), Int64 (ascending)")
+
+	query.Close()
+	_, err = query.Describe()
+	assert.Err(t, err)
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+// plainBinding hides the ObjectBindingRelations implementation of the Entity binding (see model/relations.go)
+type plainBinding struct {
+	objectbox.ObjectBinding
+}
+
+// plainTestModel is the same as model.ObjectBoxModel() but with the Entity binding wrapped in plainBinding
+func plainTestModel() *objectbox.Model {
+	var m = objectbox.NewModel()
+	m.GeneratorVersion(5)
+	m.RegisterBinding(plainBinding{model.EntityBinding})
+	m.RegisterBinding(model.TestStringIdEntityBinding)
+	m.RegisterBinding(model.EntityByValueBinding)
+	m.RegisterBinding(model.TestEntityInlineBinding)
+	m.RegisterBinding(model.TestEntityRelatedBinding)
+	m.LastEntityId(5, 145948658381494339)
+	m.LastIndexId(4, 3414034888235702623)
+	m.LastRelationId(6, 3119566795324383223)
+	return m
+}
+
+func TestQueryEager(t *testing.T) {
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	var E = model.Entity_
+
+	var shared = &model.TestEntityRelated{Name: "shared", NextSlice: []model.EntityByValue{}}
+	var objects = []*model.Entity{
+		{String: "a", RelatedPtr: shared, RelatedSlice: []model.EntityByValue{{}},
+			RelatedPtrSlice: []*model.TestEntityRelated{shared, {Name: "a", NextSlice: []model.EntityByValue{}}}},
+		{String: "b", RelatedPtr: shared, RelatedSlice: []model.EntityByValue{},
+			RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+		{String: "c", RelatedSlice: []model.EntityByValue{}, RelatedPtrSlice: []*model.TestEntityRelated{}},
+	}
+	for _, object := range objects {
+		object.Related.NextSlice = []model.EntityByValue{}
+	}
+	_, err = box.PutMany(objects)
+	assert.NoErr(t, err)
+
+	// lazy relations are not read by default
+	read, err := box.Query(E.String.OrderAsc(true)).Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(read))
+	assert.True(t, read[0].RelatedPtrSlice == nil)
+	assert.Eq(t, shared.Id, read[0].RelatedPtr.Id)
+
+	// Eager reads them in a batch, the same as FetchRelatedPtrSlice()
+	// the generated EntityQuery embeds *objectbox.Query, configure it before calling the typed Find()
+	var query = box.Query(E.String.OrderAsc(true))
+	query.Eager(E.RelatedPtrSlice)
+	read, err = query.Find()
+	assert.NoErr(t, err)
+	expected, err := box.Query(E.String.OrderAsc(true)).Find()
+	assert.NoErr(t, err)
+	assert.NoErr(t, box.FetchRelatedPtrSlice(expected...))
+	assert.Eq(t, expected, read)
+	assert.Eq(t, 2, len(read[0].RelatedPtrSlice))
+	assert.Eq(t, 1, len(read[1].RelatedPtrSlice))
+	assert.Eq(t, 0, len(read[2].RelatedPtrSlice))
+	assert.True(t, read[0].RelatedPtrSlice[0] == read[1].RelatedPtrSlice[0]) // read only once
+
+	// NoEager skips the related objects read by default
+	query = box.Query(E.String.OrderAsc(true))
+	query.NoEager()
+	read, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(read))
+	assert.Eq(t, "a", read[0].String)
+	assert.True(t, read[0].RelatedPtr == nil)
+	assert.True(t, read[0].RelatedSlice == nil)
+
+	// ... and can be combined with Eager, loading only the requested relations
+	query = box.Query(E.String.OrderAsc(true))
+	query.NoEager().Eager(E.RelatedSlice)
+	read, err = query.Find()
+	assert.NoErr(t, err)
+	assert.True(t, read[0].RelatedPtr == nil)
+	assert.Eq(t, 1, len(read[0].RelatedSlice))
+	assert.Eq(t, 0, len(read[1].RelatedSlice))
+	assert.True(t, read[0].RelatedPtrSlice == nil)
+
+	// ForEach
+	var count = 0
+	assert.NoErr(t, box.Query(E.String.Equals("a", true)).Eager(E.RelatedPtrSlice).ForEach(func(object interface{}) (bool, error) {
+		count++
+		assert.Eq(t, 2, len(object.(*model.Entity).RelatedPtrSlice))
+		return true, nil
+	}))
+	assert.Eq(t, 1, count)
+}
+
+func TestQueryEagerForEachChunks(t *testing.T) {
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	var E = model.Entity_
+
+	// more than fits into a single chunk
+	var objects = make([]*model.Entity, 250)
+	for i := range objects {
+		objects[i] = &model.Entity{
+			Int:             i,
+			RelatedSlice:    []model.EntityByValue{},
+			RelatedPtrSlice: []*model.TestEntityRelated{{Name: fmt.Sprint(i), NextSlice: []model.EntityByValue{}}},
+		}
+		objects[i].Related.NextSlice = []model.EntityByValue{}
+	}
+	_, err = box.PutMany(objects)
+	assert.NoErr(t, err)
+
+	var visited = 0
+	assert.NoErr(t, box.Query(E.Int.OrderAsc()).Eager(E.RelatedPtrSlice).ForEach(func(object interface{}) (bool, error) {
+		var entity = object.(*model.Entity)
+		assert.Eq(t, visited, entity.Int)
+		assert.Eq(t, 1, len(entity.RelatedPtrSlice))
+		assert.Eq(t, fmt.Sprint(entity.Int), entity.RelatedPtrSlice[0].Name)
+		visited++
+		return true, nil
+	}))
+	assert.Eq(t, len(objects), visited)
+
+	// stops in the middle of a chunk
+	visited = 0
+	assert.NoErr(t, box.Query().Eager(E.RelatedPtrSlice).ForEach(func(object interface{}) (bool, error) {
+		visited++
+		return visited < 150, nil
+	}))
+	assert.Eq(t, 150, visited)
+
+	// an error is passed through
+	var errStop = fmt.Errorf("stop")
+	assert.Eq(t, errStop, box.Query().Eager(E.RelatedPtrSlice).ForEach(func(object interface{}) (bool, error) {
+		return true, errStop
+	}))
+}
+
+func TestQueryEagerFallback(t *testing.T) {
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(plainTestModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	var E = model.Entity_
+
+	var object = &model.Entity{
+		RelatedSlice:    []model.EntityByValue{{Text: "value"}},
+		RelatedPtrSlice: []*model.TestEntityRelated{{Name: "related", NextSlice: []model.EntityByValue{}}},
+	}
+	object.Related.NextSlice = []model.EntityByValue{}
+	_, err = box.Put(object)
+	assert.NoErr(t, err)
+
+	// Eager assigns the targets to the field of the matching type
+	var query = box.Query()
+	query.Eager(E.RelatedPtrSlice)
+	read, err := query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(read))
+	assert.Eq(t, 1, len(read[0].RelatedPtrSlice))
+	assert.Eq(t, "related", read[0].RelatedPtrSlice[0].Name)
+
+	// NoEager needs the binding support on entities with relations...
+	_, err = box.Query().NoEager().Find()
+	assert.Err(t, err)
+
+	// ... but not on entities without any
+	_, err = model.BoxForEntityByValue(ob).Query().NoEager().Find()
+	assert.NoErr(t, err)
+}
+
+func TestQueryEagerErrors(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	// a relation of another entity
+	_, err := env.Box.Query().Eager(model.TestEntityRelated_.NextSlice).Find()
+	assert.Err(t, err)
+}
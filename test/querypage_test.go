@@ -0,0 +1,252 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+// readPages reads all pages of the given query and returns the IDs of the read objects
+func readPages(t *testing.T, query *model.EntityQuery, limit uint64) []uint64 {
+	var ids []uint64
+	for {
+		objects, token, err := query.FindPage(limit)
+		assert.NoErr(t, err)
+
+		var entities = objects.([]*model.Entity)
+		assert.True(t, uint64(len(entities)) <= limit)
+		for _, entity := range entities {
+			ids = append(ids, entity.Id)
+		}
+
+		if token == "" {
+			return ids
+		}
+		assert.Eq(t, int(limit), len(entities))
+	}
+}
+
+func TestQueryFindPage(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(100)
+	var box = model.BoxForEntity(env.ObjectBox)
+	var E = model.Entity_
+
+	allIds, err := box.Query().FindIds()
+	assert.NoErr(t, err)
+	sort.Slice(allIds, func(i, j int) bool { return allIds[i] < allIds[j] })
+
+	assert.EqItems(t, allIds, readPages(t, box.Query(), 7))
+	assert.EqItems(t, allIds, readPages(t, box.Query(), 10))
+	assert.EqItems(t, allIds, readPages(t, box.Query(), 1000))
+
+	// parameters changed on the query apply to the pages as well
+	var query = box.Query(E.Int64.GreaterThan(0).Alias("min"))
+	assert.NoErr(t, query.SetInt64Params(objectbox.Alias("min"), 47*1000000))
+	expectedIds, err := query.FindIds()
+	assert.NoErr(t, err)
+	sort.Slice(expectedIds, func(i, j int) bool { return expectedIds[i] < expectedIds[j] })
+	assert.True(t, len(expectedIds) > 0 && len(expectedIds) < len(allIds))
+	assert.EqItems(t, expectedIds, readPages(t, query, 9))
+
+	// continue from a given object or token
+	query = box.Query()
+	objects, token, err := query.FindPage(10)
+	assert.NoErr(t, err)
+	var firstPage = objects.([]*model.Entity)
+	assert.Eq(t, 10, len(firstPage))
+
+	objects, _, err = query.After(firstPage[4]).FindPage(3)
+	assert.NoErr(t, err)
+	assert.EqItems(t, firstPage[5:8], objects.([]*model.Entity))
+
+	objects, _, err = query.AfterToken(token).FindPage(1)
+	assert.NoErr(t, err)
+	assert.Eq(t, allIds[10], objects.([]*model.Entity)[0].Id)
+
+	objects, _, err = query.AfterToken("").FindPage(1)
+	assert.NoErr(t, err)
+	assert.Eq(t, allIds[0], objects.([]*model.Entity)[0].Id)
+}
+
+func TestQueryFindPageErrors(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+
+	_, _, err := env.Box.Query().FindPage(0)
+	assert.Err(t, err)
+
+	_, _, err = env.Box.Query().AfterToken("invalid").FindPage(1)
+	assert.Err(t, err)
+
+	// only a single order property is supported
+	_, _, err = env.Box.Query(E.Int.OrderAsc(), E.Int64.OrderAsc()).FindPage(1)
+	assert.Err(t, err)
+
+	// unsupported order property type
+	_, _, err = env.Box.Query(E.Bool.OrderAsc()).FindPage(1)
+	assert.Err(t, err)
+
+	// a token of an unordered query used on an ordered one and vice versa
+	_, _, err = env.Box.Query(E.Int.OrderAsc()).AfterToken("1").FindPage(1)
+	assert.Err(t, err)
+	_, _, err = env.Box.Query().AfterToken("1:1").FindPage(1)
+	assert.Err(t, err)
+}
+
+func TestQueryFindPageOrdered(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+
+	// repeating values so that the pages need to continue in the middle of a group of equal values
+	var objects []*model.Entity
+	for i := 0; i < 50; i++ {
+		objects = append(objects, &model.Entity{
+			Int:     i % 7,
+			String:  fmt.Sprintf("s%d", i%5),
+			Float64: float64(i%3) / 2,
+		})
+	}
+	_, err := env.Box.PutMany(objects)
+	assert.NoErr(t, err)
+
+	// expectedIds returns IDs of all objects sorted by the given key and then by ID
+	var expectedIds = func(less func(a, b *model.Entity) bool) []uint64 {
+		var sorted = append([]*model.Entity{}, objects...)
+		sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+		var ids = make([]uint64, len(sorted))
+		for i, object := range sorted {
+			ids[i] = object.Id
+		}
+		return ids
+	}
+
+	var intAsc = expectedIds(func(a, b *model.Entity) bool { return a.Int < b.Int })
+	var intDesc = expectedIds(func(a, b *model.Entity) bool { return a.Int > b.Int })
+	var stringAsc = expectedIds(func(a, b *model.Entity) bool { return a.String < b.String })
+	var stringDesc = expectedIds(func(a, b *model.Entity) bool { return a.String > b.String })
+	var floatAsc = expectedIds(func(a, b *model.Entity) bool { return a.Float64 < b.Float64 })
+	var floatDesc = expectedIds(func(a, b *model.Entity) bool { return a.Float64 > b.Float64 })
+
+	for _, limit := range []uint64{1, 4, 7, 100} {
+		assert.Eq(t, intAsc, readPages(t, env.Box.Query(E.Int.OrderAsc()), limit))
+		assert.Eq(t, intDesc, readPages(t, env.Box.Query(E.Int.OrderDesc()), limit))
+		assert.Eq(t, stringAsc, readPages(t, env.Box.Query(E.String.OrderAsc(true)), limit))
+		assert.Eq(t, stringDesc, readPages(t, env.Box.Query(E.String.OrderDesc(true)), limit))
+		assert.Eq(t, floatAsc, readPages(t, env.Box.Query(E.Float64.OrderAsc()), limit))
+		assert.Eq(t, floatDesc, readPages(t, env.Box.Query(E.Float64.OrderDesc()), limit))
+	}
+
+	// conditions apply as well
+	var query = env.Box.Query(E.Int.GreaterThan(3), E.Int.OrderDesc())
+	var expected []uint64
+	for _, id := range intDesc {
+		if objects[id-1].Int > 3 {
+			expected = append(expected, id)
+		}
+	}
+	assert.Eq(t, expected, readPages(t, query, 3))
+
+	// continue from a given token or object
+	query = env.Box.Query(E.String.OrderDesc(true))
+	page, token, err := query.FindPage(12)
+	assert.NoErr(t, err)
+	assert.Eq(t, stringDesc[:12], idsOf(page.([]*model.Entity)))
+
+	page, _, err = query.AfterToken(token).FindPage(5)
+	assert.NoErr(t, err)
+	assert.Eq(t, stringDesc[12:17], idsOf(page.([]*model.Entity)))
+
+	page, _, err = query.After(objects[stringDesc[20]-1]).FindPage(5)
+	assert.NoErr(t, err)
+	assert.Eq(t, stringDesc[21:26], idsOf(page.([]*model.Entity)))
+}
+
+func idsOf(objects []*model.Entity) []uint64 {
+	var ids = make([]uint64, len(objects))
+	for i, object := range objects {
+		ids[i] = object.Id
+	}
+	return ids
+}
+
+func TestQueryFindPageDynamic(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	box, err := env.ObjectBox.DynamicBox("Entity")
+	assert.NoErr(t, err)
+
+	var query = box.Query()
+	objects, token, err := query.FindPage(6)
+	assert.NoErr(t, err)
+	var page = objects.([]map[string]interface{})
+	assert.Eq(t, 6, len(page))
+	assert.True(t, token != "")
+
+	objects, _, err = query.After(page[2]).FindPage(6)
+	assert.NoErr(t, err)
+	assert.Eq(t, 6, len(objects.([]map[string]interface{})))
+	assert.Eq(t, page[3]["Id"], objects.([]map[string]interface{})[0]["Id"])
+}
+
+func TestQueryFindPageOrderedUnsigned(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	// values above math.MaxInt64 must be ordered after the smaller ones, i.e. compared as unsigned
+	var values = []uint64{1 << 63, 5, math.MaxUint64, 1, 1<<63 + 1, 5}
+	var objects []*model.Entity
+	for _, value := range values {
+		objects = append(objects, &model.Entity{Uint64: value})
+	}
+	ids, err := env.Box.PutMany(objects)
+	assert.NoErr(t, err)
+
+	var query = env.Box.Query(model.Entity_.Uint64.OrderAsc())
+	assert.Eq(t, []uint64{ids[3], ids[1], ids[5], ids[0], ids[4], ids[2]}, readPages(t, query, 2))
+}
+
+func TestQueryFindPageParamsCopied(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(10)
+
+	// the slice passed to Set*Params() may be reused by the caller after the call
+	var query = env.Box.Query(model.Entity_.Int64.Between(0, 0))
+	var params = []int64{0, math.MaxInt64}
+	assert.NoErr(t, query.SetInt64Params(model.Entity_.Int64, params...))
+	params[1] = 0
+
+	assert.Eq(t, 10, len(readPages(t, query, 3)))
+}
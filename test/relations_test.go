@@ -259,3 +259,113 @@ func TestRelationsMissing(t *testing.T) {
 	assert.True(t, 0 == len(read.RelatedSlice))
 	assert.True(t, nil == read.RelatedPtrSlice)
 }
+
+func TestRelationsBacklinks(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+	var relBox = model.BoxForTestEntityRelated(env.ObjectBox)
+
+	var shared = &model.TestEntityRelated{Name: "Shared", NextSlice: []model.EntityByValue{}}
+	var a = &model.Entity{
+		String:          "a",
+		Related:         model.TestEntityRelated{Name: "A", NextSlice: []model.EntityByValue{}},
+		RelatedPtr:      shared,
+		RelatedPtrSlice: []*model.TestEntityRelated{shared},
+	}
+	var b = &model.Entity{
+		String:          "b",
+		Related:         model.TestEntityRelated{Name: "B", NextSlice: []model.EntityByValue{}},
+		RelatedPtr:      shared,
+		RelatedPtrSlice: []*model.TestEntityRelated{shared},
+	}
+	_, err := env.Box.PutMany([]*model.Entity{a, b})
+	assert.NoErr(t, err)
+
+	ids, err := env.Box.BacklinkIds(E.RelatedPtr, shared.Id)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{a.Id, b.Id}, ids)
+
+	ids, err = env.Box.BacklinkIds(E.Related, a.Related.Id)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{a.Id}, ids)
+
+	ids, err = env.Box.BacklinkIds(E.RelatedPtr2, shared.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(ids))
+
+	ids, err = env.Box.RelationBacklinkIds(E.RelatedPtrSlice, shared.Id)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{a.Id, b.Id}, ids)
+
+	// the box must be the one of the relation source
+	_, err = relBox.BacklinkIds(E.RelatedPtr, shared.Id)
+	assert.Err(t, err)
+	_, err = relBox.RelationBacklinkIds(E.RelatedPtrSlice, shared.Id)
+	assert.Err(t, err)
+
+	// queries on the target entity, with conditions on the source entity
+	ids, err = relBox.Query(E.Related.Backlink(E.String.Equals("b", true))).FindIds()
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{b.Related.Id}, ids)
+
+	ids, err = relBox.Query(E.RelatedPtr.Backlink(E.String.Equals("b", true))).FindIds()
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{shared.Id}, ids)
+
+	ids, err = relBox.Query(E.RelatedPtrSlice.Backlink(E.String.Equals("a", true))).FindIds()
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{shared.Id}, ids)
+
+	// a backlink can only be used in a query on the relation target
+	_, err = env.Box.QueryOrError(E.Related.Backlink())
+	assert.Err(t, err)
+	_, err = env.Box.QueryOrError(E.RelatedPtrSlice.Backlink())
+	assert.Err(t, err)
+}
+
+func TestRelationsMany(t *testing.T) {
+	var env = model.NewTestEnv(t)
+	defer env.Close()
+
+	var E = model.Entity_
+	var relBox = model.BoxForTestEntityRelated(env.ObjectBox)
+
+	var targets = []*model.TestEntityRelated{
+		{Name: "1", NextSlice: []model.EntityByValue{}},
+		{Name: "2", NextSlice: []model.EntityByValue{}},
+		{Name: "3", NextSlice: []model.EntityByValue{}},
+	}
+	targetIds, err := relBox.PutMany(targets)
+	assert.NoErr(t, err)
+
+	var sources = []*model.Entity{
+		{Related: model.TestEntityRelated{NextSlice: []model.EntityByValue{}}},
+		{Related: model.TestEntityRelated{NextSlice: []model.EntityByValue{}}},
+	}
+	sourceIds, err := env.Box.PutMany(sources)
+	assert.NoErr(t, err)
+
+	assert.NoErr(t, env.Box.RelationPutMany(E.RelatedPtrSlice, sourceIds[0], targetIds...))
+	assert.NoErr(t, env.Box.RelationPutMany(E.RelatedPtrSlice, sourceIds[1], targetIds[1]))
+	assert.NoErr(t, env.Box.RelationPutMany(E.RelatedPtrSlice, sourceIds[1]))
+
+	related, err := env.Box.RelationIdsMany(E.RelatedPtrSlice, sourceIds[0], sourceIds[1], 999)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(related))
+	assert.EqItems(t, targetIds, related[sourceIds[0]])
+	assert.EqItems(t, []uint64{targetIds[1]}, related[sourceIds[1]])
+	assert.Eq(t, 0, len(related[999]))
+
+	assert.NoErr(t, env.Box.RelationRemoveMany(E.RelatedPtrSlice, sourceIds[0], targetIds[0], targetIds[2]))
+
+	related, err = env.Box.RelationIdsMany(E.RelatedPtrSlice, sourceIds...)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{targetIds[1]}, related[sourceIds[0]])
+	assert.EqItems(t, []uint64{targetIds[1]}, related[sourceIds[1]])
+
+	related, err = env.Box.RelationIdsMany(E.RelatedPtrSlice)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(related))
+}
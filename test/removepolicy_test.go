@@ -0,0 +1,262 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+// removePolicyEnv opens a store with the given remove policies and inserts two Entity objects, both referencing the
+// returned shared TestEntityRelated object through RelatedPtr and RelatedPtrSlice
+func removePolicyEnv(t *testing.T, configure func(builder *objectbox.Builder)) (*objectbox.ObjectBox, []*model.Entity, *model.TestEntityRelated) {
+	var builder = objectbox.NewBuilder().TempDirectory().Model(model.ObjectBoxModel())
+	configure(builder)
+	ob, err := builder.BuildOrError()
+	assert.NoErr(t, err)
+
+	var shared = &model.TestEntityRelated{Name: "shared", NextSlice: []model.EntityByValue{}}
+	var objects = []*model.Entity{
+		{String: "a", RelatedPtr: shared, RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+		{String: "b", RelatedPtr: shared, RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+	}
+	for _, object := range objects {
+		object.Related.NextSlice = []model.EntityByValue{}
+	}
+	_, err = model.BoxForEntity(ob).PutMany(objects)
+	assert.NoErr(t, err)
+
+	return ob, objects, shared
+}
+
+func TestRemovePolicyNone(t *testing.T) {
+	ob, objects, shared := removePolicyEnv(t, func(builder *objectbox.Builder) {})
+	defer ob.Close()
+
+	assert.NoErr(t, model.BoxForTestEntityRelated(ob).Remove(shared))
+
+	count, err := model.BoxForEntity(ob).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(len(objects)), count)
+}
+
+func TestRemovePolicyRestrict(t *testing.T) {
+	ob, _, shared := removePolicyEnv(t, func(builder *objectbox.Builder) {
+		builder.RemovePolicy(model.Entity_.RelatedPtr, objectbox.RemovePolicyRestrict)
+	})
+	defer ob.Close()
+
+	var relBox = model.BoxForTestEntityRelated(ob)
+	var err = relBox.Remove(shared)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "Entity.RelatedPtr"))
+	assert.True(t, strings.Contains(err.Error(), "restrict"))
+
+	_, err = relBox.RemoveIds(shared.Id)
+	assert.Err(t, err)
+	_, err = relBox.Query(model.TestEntityRelated_.Name.Equals("shared", true)).Remove()
+	assert.Err(t, err)
+	assert.Err(t, relBox.RemoveAll())
+
+	// nothing has been removed
+	contains, err := relBox.Contains(shared.Id)
+	assert.NoErr(t, err)
+	assert.True(t, contains)
+
+	// objects not referenced anymore can be removed
+	assert.NoErr(t, model.BoxForEntity(ob).RemoveAll())
+	assert.NoErr(t, relBox.Remove(shared))
+	assert.NoErr(t, relBox.RemoveAll())
+}
+
+func TestRemovePolicyCascade(t *testing.T) {
+	ob, _, _ := removePolicyEnv(t, func(builder *objectbox.Builder) {
+		builder.RemovePolicy(model.Entity_.RelatedPtr, objectbox.RemovePolicyCascade)
+	})
+	defer ob.Close()
+
+	var box = model.BoxForEntity(ob)
+	var relBox = model.BoxForTestEntityRelated(ob)
+
+	// an unrelated object stays
+	id, err := box.Put(&model.Entity{String: "c", Related: model.TestEntityRelated{NextSlice: []model.EntityByValue{}}})
+	assert.NoErr(t, err)
+
+	count, err := relBox.Query(model.TestEntityRelated_.Name.Equals("shared", true)).Remove()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	ids, err := box.Query().FindIds()
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{id}, ids)
+}
+
+func TestRemovePolicyNullify(t *testing.T) {
+	ob, objects, shared := removePolicyEnv(t, func(builder *objectbox.Builder) {
+		builder.RemovePolicy(model.Entity_.RelatedPtr, objectbox.RemovePolicyNullify)
+		builder.RelationRemovePolicy(model.Entity_.RelatedPtrSlice, objectbox.RemovePolicyNullify)
+	})
+	defer ob.Close()
+
+	assert.NoErr(t, model.BoxForTestEntityRelated(ob).RemoveId(shared.Id))
+
+	dynamicBox, err := ob.DynamicBox("Entity")
+	assert.NoErr(t, err)
+
+	for _, object := range objects {
+		values, err := dynamicBox.Get(object.Id)
+		assert.NoErr(t, err)
+		assert.Eq(t, object.String, values["String"])
+		assert.Eq(t, uint64(0), values["RelatedPtr"])
+		assert.Eq(t, object.Related.Id, values["Related"])
+
+		ids, err := ob.InternalBox(model.EntityBinding.Id).RelationIds(model.Entity_.RelatedPtrSlice, object.Id)
+		assert.NoErr(t, err)
+		assert.Eq(t, 0, len(ids))
+	}
+}
+
+func TestRemovePolicyRestrictCascaded(t *testing.T) {
+	ob, objects, shared := removePolicyEnv(t, func(builder *objectbox.Builder) {
+		builder.RemovePolicy(model.Entity_.RelatedPtr, objectbox.RemovePolicyRestrict)
+		builder.RelationRemovePolicy(model.Entity_.RelatedPtrSlice, objectbox.RemovePolicyCascade)
+	})
+	defer ob.Close()
+
+	// all objects referencing the shared one through the restricted relation are removed by the same call
+	assert.NoErr(t, model.BoxForTestEntityRelated(ob).Remove(shared))
+
+	count, err := model.BoxForEntity(ob).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(0), count)
+
+	// unless there's one more, not removed by the cascade
+	shared.Id = 0
+	objects[0].Id = 0
+	objects[0].RelatedPtrSlice = nil
+	objects[1].Id = 0
+	_, err = model.BoxForEntity(ob).PutMany(objects)
+	assert.NoErr(t, err)
+
+	err = model.BoxForTestEntityRelated(ob).Remove(shared)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "referenced by 1 object(s)"))
+
+	count, err = model.BoxForEntity(ob).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+}
+
+// treeBinding declares a self-relation, which isn't supported by the generator, to be used through DynamicBox
+type treeBinding struct {
+	objectbox.ObjectBinding
+	policy objectbox.RemovePolicy
+}
+
+func (binding treeBinding) AddToModel(model *objectbox.Model) {
+	model.Entity("Tree", 1, 7503446476155339040)
+	model.Property("Id", 6, 1, 3257236306373960405)
+	model.PropertyFlags(1)
+	model.Property("Name", 9, 2, 5826519434428958373)
+	model.Property("Parent", 11, 3, 2197312485093446218)
+	model.PropertyFlags(520)
+	model.PropertyRelation("Tree", 1, 4306398731096330129)
+	model.RelationRemovePolicy(binding.policy)
+	model.EntityLastPropertyId(3, 2197312485093446218)
+}
+
+func (binding treeBinding) GeneratorVersion() int {
+	return 5
+}
+
+func TestRemovePolicyRestrictSelfRelation(t *testing.T) {
+	var m = objectbox.NewModel()
+	m.GeneratorVersion(5)
+	m.RegisterBinding(treeBinding{policy: objectbox.RemovePolicyRestrict})
+	m.LastEntityId(1, 7503446476155339040)
+	m.LastIndexId(1, 4306398731096330129)
+
+	ob, err := objectbox.NewBuilder().TempDirectory().Model(m).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	box, err := ob.DynamicBox("Tree")
+	assert.NoErr(t, err)
+
+	parentId, err := box.Put(map[string]interface{}{"Name": "parent"})
+	assert.NoErr(t, err)
+	childId, err := box.Put(map[string]interface{}{"Name": "child", "Parent": parentId})
+	assert.NoErr(t, err)
+	otherId, err := box.Put(map[string]interface{}{"Name": "other"})
+	assert.NoErr(t, err)
+
+	// the parent is still referenced by the child
+	assert.Err(t, box.RemoveId(parentId))
+	_, err = box.RemoveIds(parentId, otherId)
+	assert.Err(t, err)
+
+	// both removed at once
+	count, err := box.RemoveIds(childId, parentId)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), count)
+
+	ids, err := box.Query().FindIds()
+	assert.NoErr(t, err)
+	assert.EqItems(t, []uint64{otherId}, ids)
+}
+
+func TestRemovePolicyRestrictStandalone(t *testing.T) {
+	ob, _, shared := removePolicyEnv(t, func(builder *objectbox.Builder) {
+		builder.RelationRemovePolicy(model.Entity_.RelatedPtrSlice, objectbox.RemovePolicyRestrict)
+	})
+	defer ob.Close()
+
+	assert.Err(t, model.BoxForTestEntityRelated(ob).Remove(shared))
+}
+
+func TestRemovePolicyConfiguration(t *testing.T) {
+	var builder = objectbox.NewBuilder().RemovePolicy(model.Entity_.RelatedPtr, objectbox.RemovePolicyCascade)
+	assert.Err(t, builder.Error)
+
+	builder = objectbox.NewBuilder().Model(model.ObjectBoxModel())
+	builder.RemovePolicy(&objectbox.RelationToOne{
+		Property: &objectbox.BaseProperty{Id: 999, Entity: &objectbox.Entity{Id: model.EntityBinding.Id}},
+		Target:   &objectbox.Entity{Id: model.TestEntityRelatedBinding.Id},
+	}, objectbox.RemovePolicyCascade)
+	assert.Err(t, builder.Error)
+
+	builder = objectbox.NewBuilder().Model(model.ObjectBoxModel())
+	builder.RelationRemovePolicy(&objectbox.RelationToMany{
+		Id:     999,
+		Source: &objectbox.Entity{Id: model.EntityBinding.Id},
+		Target: &objectbox.Entity{Id: model.TestEntityRelatedBinding.Id},
+	}, objectbox.RemovePolicyCascade)
+	assert.Err(t, builder.Error)
+
+	ob, _, _ := removePolicyEnv(t, func(builder *objectbox.Builder) {
+		builder.RemovePolicy(model.Entity_.RelatedPtr, objectbox.RemovePolicyRestrict)
+	})
+	defer ob.Close()
+
+	assert.Eq(t, objectbox.RemovePolicyRestrict, ob.Entity("Entity").Property("RelatedPtr").RemovePolicy)
+	assert.Eq(t, objectbox.RemovePolicyNone, ob.Entity("Entity").Property("RelatedPtr2").RemovePolicy)
+	assert.Eq(t, "restrict", objectbox.RemovePolicyRestrict.String())
+}
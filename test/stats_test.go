@@ -0,0 +1,76 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestStats(t *testing.T) {
+	ob, err := objectbox.NewBuilder().TempDirectory().MaxSizeInKb(100 * 1024).Model(model.ObjectBoxModel()).BuildOrError()
+	assert.NoErr(t, err)
+	defer ob.Close()
+
+	stats, err := ob.Stats()
+	assert.NoErr(t, err)
+	assert.True(t, stats.FileSize > 0)
+	assert.Eq(t, uint64(100*1024*1024), stats.MaxSize)
+	assert.Eq(t, uint64(0), stats.EntityCounts["Entity"])
+	assert.Eq(t, uint64(0), stats.ExplicitWriteTransactionsCommitted)
+
+	var box = model.BoxForEntity(ob)
+	assert.NoErr(t, ob.RunInWriteTx(func() error {
+		_, err := box.PutMany([]*model.Entity{model.Entity47(), model.Entity47()})
+		return err
+	}))
+	assert.Err(t, ob.RunInWriteTx(func() error {
+		return errors.New("rollback")
+	}))
+
+	tx, err := ob.BeginRead()
+	assert.NoErr(t, err)
+
+	stats, err = ob.Stats()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(2), stats.EntityCounts["Entity"])
+	assert.Eq(t, uint64(0), stats.EntityCounts["TestEntityRelated"])
+	assert.Eq(t, int64(1), stats.ActiveExplicitReadTransactions)
+	assert.Eq(t, int64(0), stats.ActiveExplicitWriteTransactions)
+	assert.Eq(t, uint64(1), stats.ExplicitWriteTransactionsCommitted)
+	assert.Eq(t, uint64(1), stats.ExplicitWriteTransactionsAborted)
+	assert.NoErr(t, tx.Commit())
+
+	_, err = model.BoxForTestStringIdEntity(ob).Async().Put(&model.TestStringIdEntity{})
+	assert.NoErr(t, err)
+	assert.NoErr(t, ob.AwaitAsyncCompletion())
+
+	// a single call outside of a transaction runs in an implicit one, which isn't counted
+	_, err = model.BoxForTestStringIdEntity(ob).Put(&model.TestStringIdEntity{})
+	assert.NoErr(t, err)
+
+	stats, err = ob.Stats()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), stats.ExplicitWriteTransactionsCommitted)
+	assert.Eq(t, int64(0), stats.ActiveExplicitReadTransactions)
+	assert.Eq(t, uint64(1), stats.AsyncSubmitted)
+	assert.Eq(t, uint64(0), stats.AsyncRejected)
+}
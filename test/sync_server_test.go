@@ -18,6 +18,7 @@ package objectbox_test
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net"
 	"net/url"
 	"os"
@@ -27,6 +28,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/test/assert"
 	"github.com/objectbox/objectbox-go/test/model"
 )
@@ -49,7 +51,7 @@ type testSyncServer struct {
 	t    *testing.T
 	err  error
 	cmd  *exec.Cmd
-	env  *model.TestEnv
+	dir  string
 	port int
 }
 
@@ -70,12 +72,16 @@ func NewTestSyncServer(t *testing.T) *testSyncServer {
 	}()
 
 	// prepare a database directory
-	server.env = model.NewTestEnv(server.t)
-	server.env.ObjectBox.Close() // close the database so that the server can open it
+	var err error
+	server.dir, err = ioutil.TempDir("", "objectbox-test")
+	assert.NoErr(t, err)
+	ob, err := objectbox.NewBuilder().Directory(server.dir).Model(model.ObjectBoxModel()).Build()
+	assert.NoErr(t, err)
+	ob.Close() // close the database so that the server can open it
 
 	server.cmd = exec.Command(execPath,
 		"--unsecured-no-authentication",
-		"--db-directory="+server.env.Directory,
+		"--db-directory="+server.dir,
 		"--bind="+server.URI(),
 		"--browser-bind=127.0.0.1:"+strconv.FormatInt(int64(findFreeTCPPort(t)), 10),
 	)
@@ -133,13 +139,13 @@ func findSyncServerExecutable(t *testing.T) string {
 }
 
 func (server *testSyncServer) Close() {
-	if server.env == nil {
+	if server.dir == "" {
 		return
 	}
 
 	defer func() {
-		server.env.Close()
-		server.env = nil
+		os.RemoveAll(server.dir)
+		server.dir = ""
 		server.cmd = nil
 	}()
 
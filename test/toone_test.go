@@ -0,0 +1,108 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+)
+
+func TestToOne(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = model.BoxForTestEntityRelated(env.ObjectBox)
+	var entityId = model.TestEntityRelatedBinding.Id
+
+	id, err := box.Put(&model.TestEntityRelated{Name: "target", NextSlice: []model.EntityByValue{}})
+	assert.NoErr(t, err)
+
+	// loaded on demand and cached
+	var rel = objectbox.NewToOne[model.TestEntityRelated](env.ObjectBox, entityId, id)
+	assert.True(t, !rel.IsLoaded())
+	target, err := rel.Get()
+	assert.NoErr(t, err)
+	assert.Eq(t, "target", target.Name)
+	assert.True(t, rel.IsLoaded())
+
+	assert.NoErr(t, box.RemoveId(id))
+	target, err = rel.Get()
+	assert.NoErr(t, err)
+	assert.Eq(t, "target", target.Name)
+
+	// changing the ID invalidates the cache
+	rel.TargetId = id + 1
+	assert.True(t, !rel.IsLoaded())
+	target, err = rel.Get()
+	assert.NoErr(t, err)
+	assert.True(t, target == nil)
+
+	// empty relation
+	rel = objectbox.NewToOne[model.TestEntityRelated](env.ObjectBox, entityId, 0)
+	target, err = rel.Get()
+	assert.NoErr(t, err)
+	assert.True(t, target == nil)
+
+	// a new target is inserted by PutTarget()
+	var unattached objectbox.ToOne[model.TestEntityRelated]
+	unattached.Set(&model.TestEntityRelated{Name: "new", NextSlice: []model.EntityByValue{}})
+	target, err = unattached.Get()
+	assert.NoErr(t, err)
+	assert.Eq(t, "new", target.Name)
+	assert.NoErr(t, unattached.PutTarget(env.ObjectBox, entityId))
+	assert.True(t, unattached.TargetId != 0)
+	assert.Eq(t, unattached.TargetId, target.Id)
+
+	read, err := box.Get(unattached.TargetId)
+	assert.NoErr(t, err)
+	assert.Eq(t, "new", read.Name)
+
+	// an existing target is only referenced
+	unattached.Set(read)
+	assert.NoErr(t, unattached.PutTarget(env.ObjectBox, entityId))
+	assert.Eq(t, read.Id, unattached.TargetId)
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	unattached.Set(nil)
+	assert.Eq(t, uint64(0), unattached.TargetId)
+	assert.NoErr(t, unattached.PutTarget(env.ObjectBox, entityId))
+	assert.Eq(t, uint64(0), unattached.TargetId)
+
+	// assigning TargetId directly discards the target assigned by Set()
+	var rel2 = objectbox.NewToOne[model.TestEntityRelated](env.ObjectBox, entityId, 0)
+	rel2.Set(&model.TestEntityRelated{Name: "discarded", NextSlice: []model.EntityByValue{}})
+	rel2.TargetId = read.Id
+	assert.True(t, !rel2.IsLoaded())
+	target, err = rel2.Get()
+	assert.NoErr(t, err)
+	assert.Eq(t, "new", target.Name)
+	assert.NoErr(t, rel2.PutTarget(env.ObjectBox, entityId))
+	assert.Eq(t, read.Id, rel2.TargetId)
+	count, err = box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+
+	// reading requires the store
+	var detached = objectbox.ToOne[model.TestEntityRelated]{TargetId: 1}
+	_, err = detached.Get()
+	assert.Err(t, err)
+}
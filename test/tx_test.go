@@ -93,3 +93,104 @@ func TestTransactionRollback(t *testing.T) {
 	assert.Eq(t, 0, int(count))
 
 }
+
+func TestTxExplicit(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	// committed
+	tx, err := env.BeginWrite()
+	assert.NoErr(t, err)
+	assert.True(t, !tx.IsReadOnly())
+
+	box, err := tx.Box(iot.EventBinding.Id)
+	assert.NoErr(t, err)
+	var eventBox = &iot.EventBox{Box: box}
+	for i := 0; i < 10; i++ {
+		_, err = eventBox.Put(&iot.Event{})
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, tx.Commit())
+
+	count, err := iot.BoxForEvent(env.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	// aborted
+	tx, err = env.BeginWrite()
+	assert.NoErr(t, err)
+	box, err = tx.Box(iot.EventBinding.Id)
+	assert.NoErr(t, err)
+	assert.NoErr(t, box.RemoveAll())
+	assert.NoErr(t, tx.Abort())
+
+	count, err = iot.BoxForEvent(env.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+
+	// read
+	tx, err = env.BeginRead()
+	assert.NoErr(t, err)
+	assert.True(t, tx.IsReadOnly())
+	box, err = tx.Box(iot.EventBinding.Id)
+	assert.NoErr(t, err)
+	count, err = box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10), count)
+	assert.NoErr(t, tx.Commit())
+}
+
+func TestTxMisuse(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	tx, err := env.BeginWrite()
+	assert.NoErr(t, err)
+
+	// unknown entity
+	_, err = tx.Box(999)
+	assert.Err(t, err)
+
+	box, err := tx.Box(iot.EventBinding.Id)
+	assert.NoErr(t, err)
+	var query = box.Query()
+	defer query.Close()
+
+	// used from another goroutine, including the box and queries created from it
+	var errs = make(chan error, 6)
+	go func() {
+		_, err := tx.Box(iot.EventBinding.Id)
+		errs <- err
+		_, err = box.Put(&iot.Event{})
+		errs <- err
+		_, err = box.Count()
+		errs <- err
+		_, err = query.Find()
+		errs <- err
+		errs <- tx.Commit()
+		errs <- tx.Abort()
+	}()
+	for i := 0; i < 6; i++ {
+		assert.Err(t, <-errs)
+	}
+
+	// the box works in the creating goroutine
+	_, err = box.Put(&iot.Event{})
+	assert.NoErr(t, err)
+
+	// double close
+	assert.NoErr(t, tx.Commit())
+	assert.Err(t, tx.Commit())
+	assert.NoErr(t, tx.Abort()) // no-op
+	_, err = tx.Box(iot.EventBinding.Id)
+	assert.Err(t, err)
+
+	// the box can't be used after the transaction is finished
+	_, err = box.Count()
+	assert.Err(t, err)
+
+	// regular boxes are not affected
+	count, err := iot.BoxForEvent(env.ObjectBox).Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(1), count)
+}
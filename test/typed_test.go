@@ -0,0 +1,158 @@
+/*
+ * Copyright 2019 ObjectBox Ltd. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectbox_test
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/test/assert"
+	"github.com/objectbox/objectbox-go/test/model"
+	"github.com/objectbox/objectbox-go/test/model/iot"
+)
+
+func TestTypedBox(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	var box = objectbox.NewTypedBox[iot.Event](env.ObjectBox, iot.EventBinding.Id)
+
+	id, err := box.Put(&iot.Event{Device: "dev1"})
+	assert.NoErr(t, err)
+
+	event, err := box.Get(id)
+	assert.NoErr(t, err)
+	assert.Eq(t, "dev1", event.Device)
+
+	event, err = box.Get(id + 100)
+	assert.NoErr(t, err)
+	assert.True(t, event == nil)
+
+	ids, err := box.PutMany([]*iot.Event{{Device: "dev2"}, {Device: "dev3"}})
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(ids))
+
+	events, err := box.GetMany(id, ids[1], id+100)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(events))
+	assert.Eq(t, "dev1", events[0].Device)
+	assert.Eq(t, "dev3", events[1].Device)
+	assert.True(t, events[2] == nil)
+
+	count, err := box.Count()
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(3), count)
+
+	var query = box.Query(iot.Event_.Device.HasPrefix("dev", true)).Offset(1)
+	defer query.Close()
+
+	events, err = query.Find()
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(events))
+
+	var devices []string
+	assert.NoErr(t, query.ForEach(func(event *iot.Event) (bool, error) {
+		devices = append(devices, event.Device)
+		return true, nil
+	}))
+	assert.EqItems(t, []string{"dev2", "dev3"}, devices)
+}
+
+func TestTypedBoxByValue(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var box = objectbox.NewTypedBox[model.EntityByValue](env.ObjectBox, model.EntityByValueBinding.Id)
+
+	id, err := box.Put(&model.EntityByValue{Text: "val"})
+	assert.NoErr(t, err)
+
+	objects, err := box.GetAll()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(objects))
+	assert.Eq(t, id, objects[0].Id)
+	assert.Eq(t, "val", objects[0].Text)
+
+	// missing objects are nil, not zero values
+	objects, err = box.GetMany(id, id+1)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(objects))
+	assert.Eq(t, id, objects[0].Id)
+	assert.True(t, objects[1] == nil)
+}
+
+func TestTypedBoxInvalid(t *testing.T) {
+	env := iot.NewTestEnv()
+	defer env.Close()
+
+	_, err := objectbox.NewTypedBoxOrError[iot.Reading](env.ObjectBox, iot.EventBinding.Id)
+	assert.Err(t, err)
+
+	_, err = objectbox.NewTypedBoxOrError[iot.Event](env.ObjectBox, 999)
+	assert.Err(t, err)
+}
+
+func TestTypedQueryFindPage(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	env.Populate(20)
+	var box = objectbox.NewTypedBox[model.Entity](env.ObjectBox, model.EntityBinding.Id)
+
+	var query = box.Query()
+	first, token, err := query.FindPage(15)
+	assert.NoErr(t, err)
+	assert.Eq(t, 15, len(first))
+	assert.True(t, token != "")
+
+	second, token, err := query.FindPage(15)
+	assert.NoErr(t, err)
+	assert.Eq(t, 5, len(second))
+	assert.Eq(t, "", token)
+	assert.True(t, second[0].Id > first[14].Id)
+}
+
+func TestTypedBoxBacklinks(t *testing.T) {
+	env := model.NewTestEnv(t)
+	defer env.Close()
+
+	var shared = &model.TestEntityRelated{Name: "Shared", NextSlice: []model.EntityByValue{}}
+	var objects = []*model.Entity{
+		{String: "a", RelatedPtr: shared, RelatedPtrSlice: []*model.TestEntityRelated{shared}},
+		{String: "b", RelatedPtr: shared, RelatedPtrSlice: []*model.TestEntityRelated{}},
+	}
+	_, err := env.Box.PutMany(objects)
+	assert.NoErr(t, err)
+
+	var box = objectbox.NewTypedBox[model.Entity](env.ObjectBox, model.EntityBinding.Id)
+
+	children, err := box.Backlinks(model.Entity_.RelatedPtr, shared.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(children))
+	assert.Eq(t, "a", children[0].String)
+	assert.Eq(t, "b", children[1].String)
+
+	children, err = box.RelationBacklinks(model.Entity_.RelatedPtrSlice, shared.Id)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(children))
+	assert.Eq(t, "a", children[0].String)
+
+	_, err = box.Backlinks(model.TestEntityRelated_.Next, shared.Id)
+	assert.Err(t, err)
+	_, err = box.RelationBacklinks(model.TestEntityRelated_.NextSlice, shared.Id)
+	assert.Err(t, err)
+}